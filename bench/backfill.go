@@ -0,0 +1,372 @@
+package bench
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/podocarp/mysql-test-test/db"
+	"github.com/podocarp/mysql-test-test/utils"
+)
+
+// How long a throttled backfill waits before checking the signal again.
+const throttlePause = 100 * time.Millisecond
+
+// Converts the JSON country arrays of a table into bitsets, one chunk of rows
+// at a time in primary key order, while the table stays in use. Rows are
+// upserted into the target by id, so the target can be another table with
+// the same ids or a new column of the source table. Rows changed after their
+// chunk was converted are not picked up again; Verify finds them.
+type Backfill struct {
+	// countries_json.countries if empty.
+	Source, SourceColumn string
+	// countries_bitset.countries if empty.
+	Target, TargetColumn string
+	// Rows per chunk, 1000 if 0.
+	ChunkSize int
+	// Rows per second to stay under, no limit if 0.
+	Rate float64
+	// Called before every chunk. While it returns true, e.g. because the
+	// server is too busy, the backfill waits. See ThreadsRunningAbove and
+	// HistoryLengthAbove.
+	Throttle func(pool *sql.DB) (bool, error)
+	// File the progress is written to after every chunk. If it exists, the
+	// backfill resumes after the last id it recorded.
+	Checkpoint string
+}
+
+// Progress of a backfill, as written to the checkpoint file.
+type BackfillState struct {
+	Source string
+	Target string
+	// Last id that was converted.
+	LastID uint64
+	Rows   int64
+	// Time spent waiting on Rate and Throttle.
+	Throttled time.Duration
+}
+
+// What Verify found.
+type BackfillCheck struct {
+	Rows int64
+	// Rows whose target is NULL while the source is not.
+	Missing int64
+	// Rows whose target holds other countries than the source.
+	Mismatched int64
+	// The first few ids that are missing or mismatched.
+	IDs []uint64
+}
+
+// Ids reported in BackfillCheck.IDs at most.
+const maxCheckIDs = 10
+
+func (b Backfill) withDefaults() (Backfill, error) {
+	if b.Source == "" {
+		b.Source, b.SourceColumn = JSON{}.Table(), "countries"
+	}
+	if b.Target == "" {
+		b.Target, b.TargetColumn = Bitset{}.Table(), "countries"
+	}
+	if b.ChunkSize <= 0 {
+		b.ChunkSize = 1000
+	}
+	for _, name := range []string{b.Source, b.SourceColumn, b.Target, b.TargetColumn} {
+		if !db.ValidIdentifier(name) {
+			return b, fmt.Errorf("invalid table or column name %q", name)
+		}
+	}
+	return b, nil
+}
+
+// Converts the rows after the last checkpointed id until it reaches the end
+// of the source. Rows inserted while it runs are converted if they come
+// before the end at the time the last chunk is read. Returns the progress so
+// far with the error if it fails or ctx is cancelled; the checkpoint lets a
+// later call carry on from there.
+func (b Backfill) Run(ctx context.Context, pool *sql.DB) (BackfillState, error) {
+	b, err := b.withDefaults()
+	state := BackfillState{Source: b.Source + "." + b.SourceColumn, Target: b.Target + "." + b.TargetColumn}
+	if err != nil {
+		return state, err
+	}
+	if state, err = b.load(state); err != nil {
+		return state, err
+	}
+
+	query := fmt.Sprintf(`SELECT id, %s FROM %s WHERE id > ? ORDER BY id LIMIT %d`, b.SourceColumn, b.Source, b.ChunkSize)
+	start := time.Now()
+	rows := int64(0)
+	for {
+		if err := b.wait(ctx, pool, &state, start, rows); err != nil {
+			return state, err
+		}
+		ids, values, err := readChunk(ctx, pool, query, state.LastID)
+		if err != nil {
+			return state, err
+		}
+		if len(ids) == 0 {
+			return state, nil
+		}
+		if err := b.writeChunk(ctx, pool, ids, values); err != nil {
+			return state, err
+		}
+		state.LastID = ids[len(ids)-1]
+		state.Rows += int64(len(ids))
+		rows += int64(len(ids))
+		if err := b.save(state); err != nil {
+			return state, err
+		}
+		if len(ids) < b.ChunkSize {
+			return state, nil
+		}
+	}
+}
+
+// Reads the checkpoint, if there is one, and checks that it belongs to the
+// same source and target.
+func (b Backfill) load(state BackfillState) (BackfillState, error) {
+	if b.Checkpoint == "" {
+		return state, nil
+	}
+	data, err := os.ReadFile(b.Checkpoint)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	} else if err != nil {
+		return state, err
+	}
+	var saved BackfillState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return state, fmt.Errorf("%s: %w", b.Checkpoint, err)
+	}
+	if saved.Source != state.Source || saved.Target != state.Target {
+		return state, fmt.Errorf("%s is a backfill of %s into %s", b.Checkpoint, saved.Source, saved.Target)
+	}
+	return saved, nil
+}
+
+func (b Backfill) save(state BackfillState) error {
+	if b.Checkpoint == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := b.Checkpoint + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, b.Checkpoint)
+}
+
+// Waits until the rate allows the next chunk and the throttle signal clears.
+func (b Backfill) wait(ctx context.Context, pool *sql.DB, state *BackfillState, start time.Time, rows int64) error {
+	began := time.Now()
+	defer func() { state.Throttled += time.Since(began) }()
+
+	if b.Rate > 0 {
+		ahead := time.Duration(float64(rows)/b.Rate*float64(time.Second)) - time.Since(start)
+		if err := sleep(ctx, ahead); err != nil {
+			return err
+		}
+	}
+	for b.Throttle != nil {
+		throttled, err := b.Throttle(pool)
+		if err != nil {
+			return err
+		}
+		if !throttled {
+			break
+		}
+		if err := sleep(ctx, throttlePause); err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Reads the ids and JSON arrays of a chunk. NULL arrays are returned as nil.
+func readChunk(ctx context.Context, pool *sql.DB, query string, after uint64) ([]uint64, []*utils.Countries, error) {
+	rows, err := pool.QueryContext(ctx, query, after)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	ids := []uint64{}
+	values := []*utils.Countries{}
+	for rows.Next() {
+		var id uint64
+		var j []byte
+		if err := rows.Scan(&id, &j); err != nil {
+			return nil, nil, err
+		}
+		var countries *utils.Countries
+		if j != nil {
+			countries = &utils.Countries{}
+			if err := json.Unmarshal(j, countries); err != nil {
+				return nil, nil, fmt.Errorf("row %d: %w", id, err)
+			}
+		}
+		ids = append(ids, id)
+		values = append(values, countries)
+	}
+	return ids, values, rows.Err()
+}
+
+// Upserts the bitsets of a chunk into the target in one statement.
+func (b Backfill) writeChunk(ctx context.Context, pool *sql.DB, ids []uint64, values []*utils.Countries) error {
+	placeholders := make([]string, len(ids))
+	args := make([]any, 0, 2*len(ids))
+	for i, id := range ids {
+		placeholders[i] = "(?,?)"
+		if values[i] == nil {
+			args = append(args, id, nil)
+			continue
+		}
+		v, err := values[i].Encode(utils.FormatBitset)
+		if err != nil {
+			return err
+		}
+		args = append(args, id, v)
+	}
+	_, err := pool.ExecContext(ctx, fmt.Sprintf(
+		`INSERT INTO %s (id, %s) VALUES %s AS new ON DUPLICATE KEY UPDATE %s = new.%s`,
+		b.Target, b.TargetColumn, strings.Join(placeholders, ","), b.TargetColumn, b.TargetColumn),
+		args...)
+	return err
+}
+
+// Compares every row of the source with its target, chunk by chunk.
+func (b Backfill) Verify(ctx context.Context, pool *sql.DB) (BackfillCheck, error) {
+	var check BackfillCheck
+	b, err := b.withDefaults()
+	if err != nil {
+		return check, err
+	}
+	// The target may be the source table itself.
+	query := fmt.Sprintf(`SELECT s.id, s.%s, t.%s FROM %s s LEFT JOIN %s t ON t.id = s.id
+        WHERE s.id > ? ORDER BY s.id LIMIT %d`,
+		b.SourceColumn, b.TargetColumn, b.Source, b.Target, b.ChunkSize)
+
+	last := uint64(0)
+	for {
+		n, err := b.verifyChunk(ctx, pool, query, &last, &check)
+		if err != nil || n < b.ChunkSize {
+			return check, err
+		}
+	}
+}
+
+func (b Backfill) verifyChunk(ctx context.Context, pool *sql.DB, query string, last *uint64, check *BackfillCheck) (int, error) {
+	rows, err := pool.QueryContext(ctx, query, *last)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	n := 0
+	for rows.Next() {
+		var id uint64
+		var j, bitset []byte
+		if err := rows.Scan(&id, &j, &bitset); err != nil {
+			return n, err
+		}
+		n++
+		*last = id
+		check.Rows++
+
+		bad := false
+		switch {
+		case j == nil:
+			bad = bitset != nil
+			if bad {
+				check.Mismatched++
+			}
+		case bitset == nil:
+			bad = true
+			check.Missing++
+		default:
+			var source, target utils.Countries
+			if err := json.Unmarshal(j, &source); err != nil {
+				return n, fmt.Errorf("row %d: %w", id, err)
+			}
+			if err := target.Scan(bitset); err != nil {
+				return n, fmt.Errorf("row %d: %w", id, err)
+			}
+			bad = source.ToBitset() != target.ToBitset()
+			if bad {
+				check.Mismatched++
+			}
+		}
+		if bad && len(check.IDs) < maxCheckIDs {
+			check.IDs = append(check.IDs, id)
+		}
+	}
+	return n, rows.Err()
+}
+
+// Throttles a backfill while more than n threads are running statements.
+func ThreadsRunningAbove(n int64) func(pool *sql.DB) (bool, error) {
+	return func(pool *sql.DB) (bool, error) {
+		running, err := db.ThreadsRunning(pool)
+		return running > n, err
+	}
+}
+
+// Throttles a backfill while the InnoDB history list is longer than n, see
+// db.HistoryLength.
+func HistoryLengthAbove(n int64) func(pool *sql.DB) (bool, error) {
+	return func(pool *sql.DB) (bool, error) {
+		length, err := db.HistoryLength(pool)
+		return length > n, err
+	}
+}
+
+// Times a backfill of the source strategy's table into the default target
+// while the strategy keeps writing to it. Prepare fills the source with the
+// dataset and empties the target; Run writes the dataset again, as the live
+// workload, while the backfill converts what is there. The strategy has to
+// store JSON arrays, i.e. be JSON. The checkpoint is not used.
+func BackfillWorkload(b Backfill) Workload {
+	b.Target, b.TargetColumn, b.Checkpoint = "", "", ""
+	return Workload{
+		Name: "backfill",
+		Prepare: func(ctx context.Context, pool *sql.DB, s Strategy, data []utils.Countries) error {
+			if err := s.Reset(ctx, pool); err != nil {
+				return err
+			}
+			if err := s.Write(ctx, pool, data); err != nil {
+				return err
+			}
+			return Bitset{}.Reset(ctx, pool)
+		},
+		Run: func(ctx context.Context, pool *sql.DB, s Strategy, offset int, data []utils.Countries) error {
+			b := b
+			b.Source, b.SourceColumn = s.Table(), "countries"
+			live := make(chan error, 1)
+			go func() { live <- s.Write(ctx, pool, data) }()
+			_, err := b.Run(ctx, pool)
+			return errors.Join(err, <-live)
+		},
+	}
+}
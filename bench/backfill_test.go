@@ -0,0 +1,28 @@
+package bench_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/podocarp/mysql-test-test/bench"
+)
+
+func TestBackfillChecks(t *testing.T) {
+	ctx := context.Background()
+	if _, err := (bench.Backfill{Target: "countries_bitset", TargetColumn: "countries; DROP TABLE x"}).Run(ctx, nil); err == nil {
+		t.Fatal("Expected an invalid column name to be rejected")
+	}
+
+	// A checkpoint of another backfill must not be resumed from. Both checks
+	// fail before the database is used.
+	checkpoint := filepath.Join(t.TempDir(), "backfill.json")
+	state := `{"Source": "countries_json.countries", "Target": "other.countries", "LastID": 500}`
+	if err := os.WriteFile(checkpoint, []byte(state), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (bench.Backfill{Checkpoint: checkpoint}).Run(ctx, nil); err == nil {
+		t.Fatal("Expected a checkpoint of another target to be rejected")
+	}
+}
@@ -0,0 +1,64 @@
+package bench_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/podocarp/mysql-test-test/bench"
+	"github.com/podocarp/mysql-test-test/db"
+)
+
+const rows = 1000
+
+// Run with `go test -bench . ./bench` while the server from start.sh is up.
+func BenchmarkCountries(b *testing.B) {
+	pool, err := db.Open()
+	if err != nil {
+		b.Skip("database not available: ", err)
+	}
+	defer pool.Close()
+	ctx := context.Background()
+
+	for _, w := range bench.Workloads() {
+		for _, s := range bench.Strategies() {
+			b.Run(s.Name()+"/"+w.Name, func(b *testing.B) {
+				b.ReportAllocs()
+				data := bench.Dataset(rows)
+				for range b.N {
+					b.StopTimer()
+					if err := w.Prepare(ctx, pool, s, data); err != nil {
+						b.Fatal(err)
+					}
+					b.StartTimer()
+					if err := w.Run(ctx, pool, s, 0, data); err != nil {
+						b.Fatal(err)
+					}
+				}
+				b.ReportMetric(float64(rows), "rows/op")
+				b.ReportMetric(float64(rows*b.N)/b.Elapsed().Seconds(), "rows/sec")
+			})
+		}
+	}
+}
+
+func BenchmarkJunk(b *testing.B) {
+	pool, err := db.Open()
+	if err != nil {
+		b.Skip("database not available: ", err)
+	}
+	defer pool.Close()
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for range b.N {
+		b.StopTimer()
+		if err := bench.ResetJunk(ctx, pool); err != nil {
+			b.Fatal(err)
+		}
+		b.StartTimer()
+		if err := bench.WriteJunk(ctx, pool, rows); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(rows*b.N)/b.Elapsed().Seconds(), "rows/sec")
+}
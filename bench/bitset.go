@@ -0,0 +1,90 @@
+package bench
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/podocarp/mysql-test-test/db/migrate"
+	"github.com/podocarp/mysql-test-test/utils"
+)
+
+// Stores countries as a BINARY(32) bitset, see utils.CountryBitset.
+type Bitset struct {
+	// How Write sends rows, InsertRow if empty.
+	Insert InsertMode
+	// Options of the table.
+	Storage Storage
+}
+
+const bitsetSelect = `SELECT id,countries FROM countries_bitset WHERE id=?;`
+
+func (Bitset) Name() string {
+	return "bitset"
+}
+
+func (Bitset) Table() string {
+	return "countries_bitset"
+}
+
+func (Bitset) Schema() migrate.Set {
+	return migrate.Set{Name: "bitset", Migrations: []migrate.Migration{
+		createTable("countries_bitset", `CREATE TABLE IF NOT EXISTS countries_bitset (
+          id bigint unsigned NOT NULL AUTO_INCREMENT,
+          countries BINARY(32),
+          PRIMARY KEY (id)
+        ) AUTO_INCREMENT=0 ENGINE=InnoDB`),
+	}}
+}
+
+func (s Bitset) Reset(ctx context.Context, pool *sql.DB) error {
+	return resetTable(ctx, pool, s, s.Storage)
+}
+
+func (s Bitset) Write(ctx context.Context, pool *sql.DB, data []utils.Countries) error {
+	values := make([]any, len(data))
+	for i := range data {
+		// Whatever utils.ValueFormat is, the column is BINARY(32).
+		v, err := data[i].Encode(utils.FormatBitset)
+		if err != nil {
+			return err
+		}
+		values[i] = v
+	}
+	return insertRows(ctx, pool, s.Insert, "countries_bitset", "countries", values)
+}
+
+func (Bitset) Read(ctx context.Context, pool *sql.DB, offset, n int) error {
+	for i := range n {
+		row := pool.QueryRowContext(ctx, bitsetSelect, offset+i+1)
+		var r Row
+		err := row.Scan(&r.ID, &r.Countries)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s Bitset) Queries(workload string) []Query {
+	switch workload {
+	case Write.Name:
+		return []Query{insertQuery(s.Insert, "countries_bitset", "countries", &utils.Countries{})}
+	case Read.Name:
+		return []Query{{bitsetSelect, []any{1}}}
+	}
+	return nil
+}
+
+func (s Bitset) withInsertMode(mode InsertMode) Strategy {
+	s.Insert = mode
+	return s
+}
+
+func (s Bitset) withStorage(storage Storage) Strategy {
+	s.Storage = storage
+	return s
+}
+
+func (Bitset) supportsEngine(engine string) bool {
+	return true
+}
@@ -0,0 +1,113 @@
+package bench
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// How a strategy's Write sends its rows to the server.
+type InsertMode string
+
+const (
+	// One autocommitted INSERT per row. The default.
+	InsertRow InsertMode = "row"
+	// One INSERT per row, all in a single transaction.
+	InsertTx InsertMode = "tx"
+	// Multi-row INSERTs of up to insertBatchSize rows each, autocommitted.
+	InsertBatch InsertMode = "batch"
+)
+
+// Returns all known insert modes.
+func InsertModes() []InsertMode {
+	return []InsertMode{InsertRow, InsertTx, InsertBatch}
+}
+
+// Rows per statement with InsertBatch.
+const insertBatchSize = 100
+
+// Strategies that support the insert modes implement this, see
+// WithInsertMode.
+type insertModer interface {
+	withInsertMode(mode InsertMode) Strategy
+}
+
+// Returns a copy of the strategy that writes with the given mode.
+func WithInsertMode(s Strategy, mode InsertMode) (Strategy, error) {
+	if !mode.valid() {
+		return nil, fmt.Errorf("unknown insert mode %q", mode)
+	}
+	m, ok := s.(insertModer)
+	if !ok {
+		return nil, fmt.Errorf("strategy %s does not support insert modes", s.Name())
+	}
+	return m.withInsertMode(mode), nil
+}
+
+func (m InsertMode) valid() bool {
+	for _, mode := range InsertModes() {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the INSERT statement for n rows of a single column.
+func insertSQL(table, column string, n int) string {
+	values := strings.TrimSuffix(strings.Repeat("(?),", n), ",")
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES %s;", table, column, values)
+}
+
+// Returns the statement Write runs with the given mode, with example
+// arguments, see Strategy.Queries.
+func insertQuery(mode InsertMode, table, column string, arg any) Query {
+	if mode != InsertBatch {
+		return Query{insertSQL(table, column, 1), []any{arg}}
+	}
+	args := make([]any, insertBatchSize)
+	for i := range args {
+		args[i] = arg
+	}
+	return Query{insertSQL(table, column, insertBatchSize), args}
+}
+
+// Inserts one row per value into a single column of the table.
+func insertRows(ctx context.Context, pool *sql.DB, mode InsertMode, table, column string, values []any) error {
+	switch mode {
+	case "", InsertRow:
+		query := insertSQL(table, column, 1)
+		for _, value := range values {
+			if _, err := pool.ExecContext(ctx, query, value); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case InsertTx:
+		tx, err := pool.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		query := insertSQL(table, column, 1)
+		for _, value := range values {
+			if _, err := tx.ExecContext(ctx, query, value); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+		return tx.Commit()
+
+	case InsertBatch:
+		for len(values) > 0 {
+			n := min(len(values), insertBatchSize)
+			if _, err := pool.ExecContext(ctx, insertSQL(table, column, n), values[:n]...); err != nil {
+				return err
+			}
+			values = values[n:]
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown insert mode %q", mode)
+}
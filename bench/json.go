@@ -0,0 +1,97 @@
+package bench
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+
+	"github.com/podocarp/mysql-test-test/db/migrate"
+	"github.com/podocarp/mysql-test-test/utils"
+)
+
+// Stores countries as a JSON array of their ids.
+type JSON struct {
+	// How Write sends rows, InsertRow if empty.
+	Insert InsertMode
+	// Options of the table.
+	Storage Storage
+}
+
+const jsonSelect = `SELECT id,countries FROM countries_json WHERE id=?;`
+
+func (JSON) Name() string {
+	return "json"
+}
+
+func (JSON) Table() string {
+	return "countries_json"
+}
+
+func (JSON) Schema() migrate.Set {
+	return migrate.Set{Name: "json", Migrations: []migrate.Migration{
+		createTable("countries_json", `CREATE TABLE IF NOT EXISTS countries_json (
+          id bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+          countries JSON,
+          PRIMARY KEY (id)
+        ) AUTO_INCREMENT=0 ENGINE=InnoDB`),
+	}}
+}
+
+func (s JSON) Reset(ctx context.Context, pool *sql.DB) error {
+	return resetTable(ctx, pool, s, s.Storage)
+}
+
+func (s JSON) Write(ctx context.Context, pool *sql.DB, data []utils.Countries) error {
+	values := make([]any, len(data))
+	for i, countries := range data {
+		j, err := json.Marshal(countries)
+		if err != nil {
+			return err
+		}
+		values[i] = string(j)
+	}
+	return insertRows(ctx, pool, s.Insert, "countries_json", "countries", values)
+}
+
+func (JSON) Read(ctx context.Context, pool *sql.DB, offset, n int) error {
+	for i := range n {
+		row := pool.QueryRowContext(ctx, jsonSelect, offset+i+1)
+		var r Row
+		var j []byte
+		err := row.Scan(&r.ID, &j)
+		if err != nil {
+			return err
+		}
+		err = json.Unmarshal(j, &r.Countries)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s JSON) Queries(workload string) []Query {
+	switch workload {
+	case Write.Name:
+		return []Query{insertQuery(s.Insert, "countries_json", "countries", "[]")}
+	case Read.Name:
+		return []Query{{jsonSelect, []any{1}}}
+	}
+	return nil
+}
+
+func (s JSON) withInsertMode(mode InsertMode) Strategy {
+	s.Insert = mode
+	return s
+}
+
+func (s JSON) withStorage(storage Storage) Strategy {
+	s.Storage = storage
+	return s
+}
+
+// MEMORY tables cannot hold JSON columns.
+func (JSON) supportsEngine(engine string) bool {
+	return !strings.EqualFold(engine, "MEMORY")
+}
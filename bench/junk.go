@@ -0,0 +1,135 @@
+package bench
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"strings"
+
+	"github.com/podocarp/mysql-test-test/db/migrate"
+	"github.com/podocarp/mysql-test-test/utils"
+)
+
+// Stores a random base64 string per row instead of countries, as a baseline
+// for what a JSON column costs regardless of its contents.
+type Junk struct {
+	// How Write sends rows, InsertRow if empty.
+	Insert InsertMode
+	// Options of the table.
+	Storage Storage
+}
+
+const junkSelect = `SELECT id,trash FROM junk_test WHERE id=?;`
+
+func (Junk) Name() string {
+	return "junk"
+}
+
+func (Junk) Table() string {
+	return "junk_test"
+}
+
+func (Junk) Schema() migrate.Set {
+	return migrate.Set{Name: "junk", Migrations: []migrate.Migration{
+		createTable("junk_test", `CREATE TABLE IF NOT EXISTS junk_test (
+          id bigint unsigned NOT NULL AUTO_INCREMENT,
+          trash JSON,
+          PRIMARY KEY (id)
+        ) AUTO_INCREMENT=0 ENGINE=InnoDB`),
+	}}
+}
+
+func (s Junk) Reset(ctx context.Context, pool *sql.DB) error {
+	return resetTable(ctx, pool, s, s.Storage)
+}
+
+// Inserts one junk row per element of data, the countries themselves are
+// ignored.
+func (s Junk) Write(ctx context.Context, pool *sql.DB, data []utils.Countries) error {
+	values, err := junkValues(len(data))
+	if err != nil {
+		return err
+	}
+	return insertRows(ctx, pool, s.Insert, "junk_test", "trash", values)
+}
+
+func (Junk) Read(ctx context.Context, pool *sql.DB, offset, n int) error {
+	for i := range n {
+		var id uint64
+		var trash []byte
+		if err := pool.QueryRowContext(ctx, junkSelect, offset+i+1).Scan(&id, &trash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s Junk) Queries(workload string) []Query {
+	switch workload {
+	case Write.Name:
+		return []Query{insertQuery(s.Insert, "junk_test", "trash", `"junk"`)}
+	case Read.Name:
+		return []Query{{junkSelect, []any{1}}}
+	}
+	return nil
+}
+
+func (s Junk) withInsertMode(mode InsertMode) Strategy {
+	s.Insert = mode
+	return s
+}
+
+func (s Junk) withStorage(storage Storage) Strategy {
+	s.Storage = storage
+	return s
+}
+
+// MEMORY tables cannot hold JSON columns.
+func (Junk) supportsEngine(engine string) bool {
+	return !strings.EqualFold(engine, "MEMORY")
+}
+
+// Migrates the junk table and empties it.
+func ResetJunk(ctx context.Context, pool *sql.DB) error {
+	return Junk{}.Reset(ctx, pool)
+}
+
+// Inserts rows of random strings into the junk table.
+func WriteJunk(ctx context.Context, pool *sql.DB, rows int) error {
+	return Junk{}.Write(ctx, pool, make([]utils.Countries, rows))
+}
+
+// Generates rows worth of random strings, quoted as the column is JSON.
+func junkValues(rows int) ([]any, error) {
+	values := make([]any, rows)
+	for i := range values {
+		trash, err := RandomString()
+		if err != nil {
+			return nil, err
+		}
+		j, err := json.Marshal(trash)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = string(j)
+	}
+	return values, nil
+}
+
+// Returns a base64 string of up to 100 random bytes.
+func RandomString() (string, error) {
+	nBig, err := rand.Int(rand.Reader, big.NewInt(100))
+	if err != nil {
+		return "", err
+	}
+	n := nBig.Int64()
+	b := make([]byte, n)
+	_, err = rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,459 @@
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/podocarp/mysql-test-test/db"
+	"github.com/podocarp/mysql-test-test/report"
+)
+
+// The dimensions of a matrix, in the order they appear in cell names.
+var Dimensions = []string{"strategy", "workload", "rows", "insert", "storage", "workers", "pool", "server"}
+
+// Name of the pool settings and server variables used when none are given.
+const defaultName = "default"
+
+// Describes a run as the Cartesian product of the values of every dimension,
+// e.g. loaded from a JSON file with LoadConfig.
+type Config struct {
+	Iterations int
+	// Seed of the generated data, see Runner.Seed. 0 picks one.
+	Seed   int64
+	Matrix Matrix
+	// Named pool settings, see Matrix.Pools.
+	Pools map[string]PoolSettings
+	// Named sets of global server variables, see Matrix.Servers. These come
+	// on top of db.ServerProfiles, and replace profiles of the same name.
+	Servers map[string]map[string]string
+	// Extra cells. Dimensions a rule leaves out take the first value of the
+	// matrix.
+	Include []Rule
+	// Cells matching any of these are dropped. Includes are never dropped.
+	Exclude []Rule
+	// Tables to build from the results.
+	Pivots []Pivot
+}
+
+// The values of each dimension. Left out dimensions take a single default
+// value: every strategy and workload, 1000 rows, InsertRow, InnoDB, 1 worker
+// and the default pool and server.
+type Matrix struct {
+	Strategies []string
+	Workloads  []string
+	Rows       []int
+	Inserts    []InsertMode
+	// Table options named as by Storage.Name, e.g. innodb-compressed-8.
+	// Strategies whose columns an engine cannot store are skipped.
+	Storages []string
+	Workers  []int
+	// Names of entries in Config.Pools. "default" leaves the pool's limits
+	// as they were before the matrix.
+	Pools []string
+	// Names of entries in Config.Servers or of db.ServerProfiles.
+	// "default" leaves the server variables alone.
+	Servers []string
+}
+
+// What database/sql allows when SetMaxIdleConns was never called.
+const defaultMaxIdleConns = 2
+
+// Connection pool limits, see sql.DB.SetMaxOpenConns and SetMaxIdleConns.
+type PoolSettings struct {
+	MaxOpenConns int
+	MaxIdleConns int
+}
+
+// Values of some dimensions, keyed by dimension name. A cell matches a rule if
+// it has all these values.
+type Rule map[string]any
+
+// Lays out a metric with the values of one dimension as rows and another as
+// columns. Cells that only differ in the other dimensions are averaged.
+type Pivot struct {
+	Rows   string
+	Cols   string
+	Metric string
+}
+
+// One combination of dimension values.
+type Cell struct {
+	Strategy string
+	Workload string
+	Rows     int
+	Insert   InsertMode
+	Storage  string
+	Workers  int
+	Pool     string
+	Server   string
+}
+
+// A cell and what was recorded for it.
+type CellResult struct {
+	Cell   Cell
+	Result Result
+}
+
+// Reads a config from a JSON file. Keys are matched case insensitively.
+func LoadConfig(filename string) (Config, error) {
+	var c Config
+	f, err := os.Open(filename)
+	if err != nil {
+		return c, err
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&c); err != nil {
+		return c, fmt.Errorf("%s: %w", filename, err)
+	}
+	return c, nil
+}
+
+// Returns the value of a dimension.
+func (c Cell) Get(dimension string) string {
+	switch dimension {
+	case "strategy":
+		return c.Strategy
+	case "workload":
+		return c.Workload
+	case "rows":
+		return strconv.Itoa(c.Rows)
+	case "insert":
+		return string(c.Insert)
+	case "storage":
+		return c.Storage
+	case "workers":
+		return strconv.Itoa(c.Workers)
+	case "pool":
+		return c.Pool
+	case "server":
+		return c.Server
+	}
+	return ""
+}
+
+func (c *Cell) set(dimension, value string) error {
+	var err error
+	switch dimension {
+	case "strategy":
+		c.Strategy = value
+	case "workload":
+		c.Workload = value
+	case "rows":
+		c.Rows, err = strconv.Atoi(value)
+	case "insert":
+		c.Insert = InsertMode(value)
+	case "storage":
+		c.Storage = value
+	case "workers":
+		c.Workers, err = strconv.Atoi(value)
+	case "pool":
+		c.Pool = value
+	case "server":
+		c.Server = value
+	default:
+		return fmt.Errorf("unknown dimension %q", dimension)
+	}
+	return err
+}
+
+// Names the cell like a sub-benchmark, e.g.
+// json/write/rows=1000/insert=row/storage=innodb/workers=1/pool=default/server=default.
+func (c Cell) Name() string {
+	parts := []string{c.Strategy, c.Workload}
+	for _, dimension := range Dimensions[2:] {
+		parts = append(parts, dimension+"="+c.Get(dimension))
+	}
+	return strings.Join(parts, "/")
+}
+
+func (r Rule) matches(c Cell) bool {
+	for dimension, value := range r {
+		if c.Get(dimension) != ruleValue(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Formats a value decoded from JSON the way Cell.Get does. Numbers are
+// float64, which fmt would print as 1e+06.
+func ruleValue(v any) string {
+	if f, ok := v.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+func (r Rule) validate() error {
+	for dimension := range r {
+		if !slices.Contains(Dimensions, dimension) {
+			return fmt.Errorf("unknown dimension %q", dimension)
+		}
+	}
+	return nil
+}
+
+// Fills in the default values of left out dimensions.
+func (m Matrix) withDefaults() Matrix {
+	if len(m.Strategies) == 0 {
+		for _, s := range Strategies() {
+			m.Strategies = append(m.Strategies, s.Name())
+		}
+	}
+	if len(m.Workloads) == 0 {
+		for _, w := range Workloads() {
+			m.Workloads = append(m.Workloads, w.Name)
+		}
+	}
+	if len(m.Rows) == 0 {
+		m.Rows = []int{1000}
+	}
+	if len(m.Inserts) == 0 {
+		m.Inserts = []InsertMode{InsertRow}
+	}
+	if len(m.Storages) == 0 {
+		m.Storages = []string{Storage{}.Name()}
+	}
+	if len(m.Workers) == 0 {
+		m.Workers = []int{1}
+	}
+	if len(m.Pools) == 0 {
+		m.Pools = []string{defaultName}
+	}
+	if len(m.Servers) == 0 {
+		m.Servers = []string{defaultName}
+	}
+	return m
+}
+
+// Expands the matrix into its cells, drops the excluded ones and adds the
+// included ones. Cells that share a server are next to each other, so server
+// variables change as rarely as possible, and strategies vary fastest so
+// their results can be compared as they come in.
+func (c Config) Cells() ([]Cell, error) {
+	m := c.Matrix.withDefaults()
+	for _, rule := range slices.Concat(c.Include, c.Exclude) {
+		if err := rule.validate(); err != nil {
+			return nil, err
+		}
+	}
+	for _, p := range c.Pivots {
+		if !slices.Contains(Dimensions, p.Rows) || !slices.Contains(Dimensions, p.Cols) {
+			return nil, fmt.Errorf("pivot %s by %s: unknown dimension", p.Rows, p.Cols)
+		}
+	}
+
+	cells := []Cell{}
+	for _, server := range m.Servers {
+		for _, pool := range m.Pools {
+			for _, rows := range m.Rows {
+				for _, workers := range m.Workers {
+					for _, storage := range m.Storages {
+						for _, insert := range m.Inserts {
+							for _, workload := range m.Workloads {
+								for _, strategy := range m.Strategies {
+									cell := Cell{strategy, workload, rows, insert, storage, workers, pool, server}
+									if !cell.stores() || slices.ContainsFunc(c.Exclude, func(r Rule) bool { return r.matches(cell) }) {
+										continue
+									}
+									cells = append(cells, cell)
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	for _, rule := range c.Include {
+		cell := Cell{m.Strategies[0], m.Workloads[0], m.Rows[0], m.Inserts[0], m.Storages[0], m.Workers[0], m.Pools[0], m.Servers[0]}
+		for dimension, value := range rule {
+			if err := cell.set(dimension, ruleValue(value)); err != nil {
+				return nil, fmt.Errorf("include %v: %w", rule, err)
+			}
+		}
+		if !slices.Contains(cells, cell) {
+			cells = append(cells, cell)
+		}
+	}
+
+	for _, cell := range cells {
+		if err := c.validate(cell); err != nil {
+			return nil, fmt.Errorf("%s: %w", cell.Name(), err)
+		}
+	}
+	return cells, nil
+}
+
+// Reports whether the cell's strategy can be stored as the cell asks. Invalid
+// cells are left for Config.validate to report.
+func (c Cell) stores() bool {
+	strategy, ok := StrategyByName(c.Strategy)
+	if !ok {
+		return true
+	}
+	storage, err := ParseStorage(c.Storage)
+	if err != nil {
+		return true
+	}
+	_, err = WithStorage(strategy, storage)
+	return err == nil
+}
+
+func (c Config) validate(cell Cell) error {
+	strategy, ok := StrategyByName(cell.Strategy)
+	if !ok {
+		return fmt.Errorf("unknown strategy %q", cell.Strategy)
+	}
+	storage, err := ParseStorage(cell.Storage)
+	if err != nil {
+		return err
+	}
+	if _, err := WithStorage(strategy, storage); err != nil {
+		return err
+	}
+	if _, ok := WorkloadByName(cell.Workload); !ok {
+		return fmt.Errorf("unknown workload %q", cell.Workload)
+	}
+	if !cell.Insert.valid() {
+		return fmt.Errorf("unknown insert mode %q", cell.Insert)
+	}
+	if cell.Rows <= 0 || cell.Workers <= 0 {
+		return fmt.Errorf("rows and workers must be positive")
+	}
+	if _, ok := c.Pools[cell.Pool]; !ok && cell.Pool != defaultName {
+		return fmt.Errorf("unknown pool %q", cell.Pool)
+	}
+	if _, ok := c.ServerProfile(cell.Server); !ok {
+		return fmt.Errorf("unknown server %q", cell.Server)
+	}
+	return nil
+}
+
+// Looks up a server profile in Servers, then in db.ServerProfiles. The
+// default profile sets nothing.
+func (c Config) ServerProfile(name string) (db.ServerProfile, bool) {
+	if vars, ok := c.Servers[name]; ok {
+		return db.ServerProfile{Name: name, Variables: vars}, true
+	}
+	if name == defaultName {
+		return db.ServerProfile{Name: name}, true
+	}
+	return db.ServerProfileByName(name)
+}
+
+// Returns the names of the variables set by any server profile the config
+// can refer to, see Runner.Variables.
+func (c Config) ServerVariables() []string {
+	profiles := db.ServerProfiles()
+	for name := range c.Servers {
+		p, _ := c.ServerProfile(name)
+		profiles = append(profiles, p)
+	}
+	return db.ProfileVariables(profiles...)
+}
+
+// Runs every cell in turn. The runner's Rows and Workers are set from each
+// cell and its profiles go into a subdirectory per cell. Server profiles are
+// applied with SET GLOBAL, static variables they would change are left alone,
+// and everything is restored once all cells ran. Result.Variables shows what
+// each cell actually ran with if Runner.Variables is set. Like Run, returns what was collected so far
+// with the error if a cell fails or ctx is cancelled.
+func (s *Session) RunMatrix(ctx context.Context, c Config, cells []Cell) (results []CellResult, err error) {
+	r := s.Runner
+	rows, workers, profile := r.Rows, r.Workers, r.Profile
+	// sql.DB reports its open limit but not its idle one, so the pool is only
+	// touched by cells with their own settings, and the idle limit then goes
+	// back to database/sql's default.
+	maxOpen := r.Pool.Stats().MaxOpenConnections
+	var pool *PoolSettings
+	restorePool := func() {
+		if pool != nil {
+			r.Pool.SetMaxOpenConns(maxOpen)
+			r.Pool.SetMaxIdleConns(defaultMaxIdleConns)
+			pool = nil
+		}
+	}
+	defer func() {
+		r.Rows, r.Workers, r.Profile = rows, workers, profile
+		restorePool()
+	}()
+
+	// The values of every variable any server set touched, from before the
+	// first one was applied.
+	original := map[string]string{}
+	defer func() {
+		if _, restoreErr := db.SetGlobalVariables(r.Pool, original); err == nil {
+			err = restoreErr
+		}
+	}()
+
+	server := defaultName
+	for _, cell := range cells {
+		if cell.Server != server {
+			if _, err := db.SetGlobalVariables(r.Pool, original); err != nil {
+				return results, err
+			}
+			p, _ := c.ServerProfile(cell.Server)
+			applied, err := db.ApplyProfile(r.Pool, p, false)
+			for name, value := range applied.Previous {
+				if _, ok := original[name]; !ok {
+					original[name] = value
+				}
+			}
+			if err != nil {
+				return results, fmt.Errorf("server %s: %w", cell.Server, err)
+			}
+			server = cell.Server
+		}
+
+		if cell.Pool == defaultName {
+			restorePool()
+		} else if settings := c.Pools[cell.Pool]; pool == nil || *pool != settings {
+			r.Pool.SetMaxOpenConns(settings.MaxOpenConns)
+			r.Pool.SetMaxIdleConns(settings.MaxIdleConns)
+			pool = &settings
+		}
+
+		r.Rows, r.Workers = cell.Rows, cell.Workers
+		if len(profile.Iterations) > 0 {
+			r.Profile.Dir = filepath.Join(profile.Dir, strings.ReplaceAll(cell.Name(), "/", "-"))
+		}
+		w, _ := WorkloadByName(cell.Workload)
+		strategy, _ := StrategyByName(cell.Strategy)
+		strategy, err := WithInsertMode(strategy, cell.Insert)
+		if err != nil {
+			return results, err
+		}
+		storage, _ := ParseStorage(cell.Storage)
+		if strategy, err = WithStorage(strategy, storage); err != nil {
+			return results, err
+		}
+
+		result, err := s.run(ctx, cell.Name(), w, strategy)
+		if result.Timer != nil {
+			result.Timer.SetName(cell.Name())
+			results = append(results, CellResult{cell, result})
+		}
+		if err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+// Returns the mean of a metric over the timed iterations, for pivot tables,
+// see report.MetricMean.
+func (r Result) Value(metric string) float64 {
+	return report.MetricMean(r.Timer, metric)
+}
@@ -0,0 +1,96 @@
+package bench_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/podocarp/mysql-test-test/bench"
+)
+
+const matrixConfig = `{
+  "iterations": 5,
+  "matrix": {
+    "strategies": ["json", "bitset"],
+    "workloads": ["write", "read"],
+    "rows": [1000, 1000000],
+    "inserts": ["row", "batch"]
+  },
+  "include": [{"strategy": "junk", "workload": "write"}],
+  "exclude": [{"workload": "read", "insert": "batch"}, {"rows": 1000000, "strategy": "json"}]
+}`
+
+func TestConfigCells(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "matrix.json")
+	if err := os.WriteFile(filename, []byte(matrixConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	config, err := bench.LoadConfig(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cells, err := config.Cells()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 2 strategies * 2 workloads * 2 sizes * 2 modes, minus 4 batch reads, minus
+	// the 3 remaining json cells with a million rows, plus junk.
+	if len(cells) != 10 {
+		for _, cell := range cells {
+			t.Log(cell.Name())
+		}
+		t.Fatalf("Expected 10 cells, obtained %d", len(cells))
+	}
+	if name := cells[0].Name(); name != "json/write/rows=1000/insert=row/storage=innodb/workers=1/pool=default/server=default" {
+		t.Fatalf("Unexpected first cell %s", name)
+	}
+	if last := cells[len(cells)-1]; last.Strategy != "junk" || last.Rows != 1000 || last.Insert != bench.InsertRow {
+		t.Fatalf("Expected the included cell to take the first values, obtained %s", last.Name())
+	}
+	for _, cell := range cells {
+		if cell.Strategy == "json" && cell.Rows == 1000000 {
+			t.Fatalf("Expected %s to be excluded", cell.Name())
+		}
+	}
+}
+
+func TestConfigCellsInvalid(t *testing.T) {
+	configs := []bench.Config{
+		{Matrix: bench.Matrix{Strategies: []string{"xml"}}},
+		{Matrix: bench.Matrix{Inserts: []bench.InsertMode{"bulk"}}},
+		{Matrix: bench.Matrix{Servers: []string{"turbo"}}},
+		{Matrix: bench.Matrix{Storages: []string{"innodb-zstd"}}},
+		{Exclude: []bench.Rule{{"engine": "MyISAM"}}},
+		{Pivots: []bench.Pivot{{Rows: "rows", Cols: "engine", Metric: "ms/op"}}},
+	}
+	for _, config := range configs {
+		if _, err := config.Cells(); err == nil {
+			t.Errorf("Expected %+v to be invalid", config)
+		}
+	}
+}
+
+func TestConfigCellsStorage(t *testing.T) {
+	config := bench.Config{Matrix: bench.Matrix{
+		Strategies: []string{"json", "bitset"},
+		Workloads:  []string{"write"},
+		Storages:   []string{"innodb-compressed-4", "memory"},
+	}}
+	cells, err := config.Cells()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// JSON columns cannot be stored in MEMORY tables, so that cell is skipped.
+	if len(cells) != 3 {
+		t.Fatalf("Expected 3 cells, obtained %v", cells)
+	}
+	if last := cells[2]; last.Strategy != "bitset" || last.Storage != "memory" {
+		t.Fatalf("Unexpected last cell %s", last.Name())
+	}
+
+	config.Include = []bench.Rule{{"strategy": "json", "storage": "memory"}}
+	if _, err := config.Cells(); err == nil {
+		t.Fatal("Expected including a json MEMORY cell to fail")
+	}
+}
@@ -0,0 +1,275 @@
+package bench
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/podocarp/mysql-test-test/db"
+	"github.com/podocarp/mysql-test-test/proxy"
+	"github.com/podocarp/mysql-test-test/utils"
+)
+
+// Runs workloads against strategies, timing each iteration.
+type Runner struct {
+	Pool       *sql.DB
+	Iterations int
+	// Number of rows generated for each iteration.
+	Rows int
+	// If set, iteration i of every strategy gets the dataset
+	// SeededDataset(Rows, Seed+i), so runs can be reproduced and resumed.
+	Seed int64
+	// Number of goroutines the timed part is split across, each running the
+	// workload on its own share of the dataset, so readers read disjoint
+	// ranges of ids. 0 or 1 runs it on the calling goroutine.
+	Workers int
+	// Iterations to profile instead of time. Profiles go into a subdirectory
+	// of Profile.Dir named after the workload.
+	Profile utils.ProfileSchedule
+	// Pid of a local mysqld process whose CPU and I/O is recorded for every
+	// iteration, see utils.ProcessSampler. Ignored if 0.
+	ServerPID int
+	// Record how the server's global status counters change during every
+	// iteration, see db.StatusSampler.
+	ServerStatus bool
+	// Collect performance_schema statement digests of the timed part of every
+	// iteration.
+	Digests bool
+	// Capture the plan of every statement the workload runs, once per
+	// strategy, after the first Prepare.
+	Explain bool
+	// Measure the size of the strategy's table after the last iteration.
+	Footprint bool
+	// The proxy Pool connects through, if any. Its traffic is recorded for
+	// every iteration, see proxy.Sampler.
+	Proxy *proxy.Proxy
+	// What to do when a workload fails, see utils.ErrorPolicy. Errors in
+	// Prepare always abort.
+	ErrorPolicy utils.ErrorPolicy
+	Retries     int
+	// Global server variables to read back before every strategy runs, e.g.
+	// db.ProfileVariables(db.ServerProfiles()...), so results record what the
+	// server actually ran with.
+	Variables []string
+}
+
+// Everything recorded for one strategy running one workload.
+type Result struct {
+	Strategy string
+	Workload string
+	// Named after the strategy.
+	Timer *utils.Timer
+	// Statements run by the workload, summed over all iterations, slowest
+	// first. Only collected if Runner.Digests is set.
+	Digests []db.Digest
+	// Only collected if Runner.Explain is set.
+	Plans []db.Plan
+	// Size of the table after the last iteration. Only collected if
+	// Runner.Footprint is set.
+	Footprint *db.Footprint
+	// Set if the run was cancelled before all iterations were done. The
+	// iteration that was cut short is discarded.
+	Partial bool
+	// Effective values of Runner.Variables.
+	Variables map[string]string
+}
+
+// Returns the timers of the results.
+func Timers(results []Result) []*utils.Timer {
+	timers := make([]*utils.Timer, len(results))
+	for i, result := range results {
+		timers[i] = result.Timer
+	}
+	return timers
+}
+
+// Reports whether any of the results is partial.
+func Partial(results []Result) bool {
+	for _, result := range results {
+		if result.Partial {
+			return true
+		}
+	}
+	return false
+}
+
+// Runs the workload Iterations times for every strategy, with a freshly
+// generated dataset each time. Returns one result per strategy. If the run is
+// aborted by an error, the results collected so far are returned with it,
+// including the one for the strategy that failed. The same goes for when ctx
+// is cancelled, in which case the last result is marked as partial and
+// ctx.Err() is returned.
+func (r *Runner) Run(ctx context.Context, w Workload, strategies ...Strategy) ([]Result, error) {
+	results := []Result{}
+	for _, s := range strategies {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		result, err := r.run(ctx, w, s)
+		results = append(results, result)
+		if err != nil {
+			return results, fmt.Errorf("%s/%s: %w", s.Name(), w.Name, err)
+		}
+	}
+	return results, nil
+}
+
+func (r *Runner) run(ctx context.Context, w Workload, s Strategy) (Result, error) {
+	result := Result{
+		Strategy: s.Name(),
+		Workload: w.Name,
+		Timer:    r.newTimer(w, s),
+	}
+	digests := [][]db.Digest{}
+	if len(r.Variables) > 0 {
+		variables, err := db.GlobalVariables(r.Pool, r.Variables...)
+		if err != nil {
+			return result, err
+		}
+		result.Variables = variables
+	}
+
+	for i := range r.Iterations {
+		if err := ctx.Err(); err != nil {
+			return r.cancel(result, digests, err)
+		}
+		data := r.dataset(i)
+		if err := r.prepare(ctx, w, s, data); err != nil {
+			if ctx.Err() != nil {
+				return r.cancel(result, digests, ctx.Err())
+			}
+			return result, err
+		}
+		// Plans depend on the data, so wait until the table is populated.
+		if r.Explain && i == 0 {
+			for _, q := range s.Queries(w.Name) {
+				plan, err := db.Explain(r.Pool, q.SQL, q.Args...)
+				if err != nil {
+					return result, err
+				}
+				result.Plans = append(result.Plans, plan)
+			}
+		}
+		if err := r.resetDigests(); err != nil {
+			return result, err
+		}
+
+		// A retry starts over from a freshly prepared table, so the
+		// successful attempt does the same work as any other iteration.
+		err := result.Timer.TimeItRetry(
+			func() error {
+				if err := r.prepare(ctx, w, s, data); err != nil {
+					return err
+				}
+				return r.resetDigests()
+			},
+			func() error { return r.runWorkers(ctx, w, s, data) })
+		result.Timer.ReportMetric(float64(r.Rows), "rows/op")
+		if ctx.Err() != nil {
+			result.Timer.Discard()
+			return r.cancel(result, digests, ctx.Err())
+		}
+		if err != nil {
+			return result, err
+		}
+
+		// Profiled and failed iterations have no wall time to compare
+		// against.
+		if r.Digests && !result.Timer.Untimed() {
+			d, err := db.Digests(r.Pool)
+			if err != nil {
+				return result, err
+			}
+			digests = append(digests, d)
+		}
+	}
+
+	result.Digests = db.MergeDigests(digests...)
+	if r.Footprint {
+		footprint, err := db.TableFootprint(r.Pool, s.Table())
+		if err != nil {
+			return result, err
+		}
+		result.Footprint = &footprint
+	}
+	return result, nil
+}
+
+func (r *Runner) prepare(ctx context.Context, w Workload, s Strategy, data []utils.Countries) error {
+	if err := w.Prepare(ctx, r.Pool, s, data); err != nil {
+		return fmt.Errorf("prepare: %w", err)
+	}
+	return nil
+}
+
+// Reset right before the timed part so statements run by Prepare are not
+// counted.
+func (r *Runner) resetDigests() error {
+	if !r.Digests {
+		return nil
+	}
+	return db.ResetDigests(r.Pool)
+}
+
+func (r *Runner) runWorkers(ctx context.Context, w Workload, s Strategy, data []utils.Countries) error {
+	if r.Workers <= 1 {
+		return w.Run(ctx, r.Pool, s, 0, data)
+	}
+
+	errs := make([]error, r.Workers)
+	var wg sync.WaitGroup
+	for i := range r.Workers {
+		offset := i * len(data) / r.Workers
+		share := data[offset : (i+1)*len(data)/r.Workers]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = w.Run(ctx, r.Pool, s, offset, share)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+func (r *Runner) dataset(iteration int) []utils.Countries {
+	if r.Seed == 0 {
+		return Dataset(r.Rows)
+	}
+	return SeededDataset(r.Rows, r.Seed+int64(iteration))
+}
+
+// Marks the result as partial and keeps whatever digests were collected. The
+// footprint is skipped, the table may be half written.
+func (r *Runner) cancel(result Result, digests [][]db.Digest, err error) (Result, error) {
+	result.Partial = true
+	result.Digests = db.MergeDigests(digests...)
+	return result, err
+}
+
+func (r *Runner) newTimer(w Workload, s Strategy) *utils.Timer {
+	timer := utils.NewTimer(s.Name()).SetSilent().
+		SetErrorPolicy(r.ErrorPolicy, r.Retries).
+		SetErrorClassifier(db.ClassifyError)
+	if len(r.Profile.Iterations) > 0 {
+		schedule := r.Profile
+		schedule.Dir = filepath.Join(schedule.Dir, w.Name)
+		timer.SetProfileSchedule(schedule)
+	}
+	// Outside the runtime and rusage samplers, so their reads of /proc and
+	// status queries do not count as client allocations or CPU. The status
+	// queries go outermost so they do not count as server CPU either.
+	if r.ServerPID != 0 {
+		timer.AddOuterSampler(utils.NewProcessSampler(r.ServerPID))
+	}
+	if r.ServerStatus {
+		timer.AddOuterSampler(db.NewStatusSampler(r.Pool))
+	}
+	// Added last so the queries of the other samplers are not counted.
+	if r.Proxy != nil {
+		timer.AddSampler(r.Proxy.Sampler())
+	}
+	return timer
+}
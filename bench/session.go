@@ -0,0 +1,273 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/podocarp/mysql-test-test/db"
+	"github.com/podocarp/mysql-test-test/utils"
+)
+
+// A Session runs workloads like Runner.Run, but checkpoints every finished
+// strategy/workload cell to a state file. If the program crashes or is
+// interrupted, opening the session again picks up at the first unfinished
+// cell. Cells are the unit of work: a cell that was cut short runs again from
+// its first iteration.
+type Session struct {
+	Runner   *Runner
+	Filename string
+	state    sessionState
+}
+
+// What is written to the state file.
+type sessionState struct {
+	Seed int64
+	// The runner's settings and the caller's config, see OpenSession.
+	Config map[string]string
+	Cells  []cellState
+}
+
+// A finished strategy/workload cell.
+type cellState struct {
+	// Strategy/workload, or Cell.Name for matrix cells.
+	Key      string
+	Strategy string
+	Workload string
+	// The timer in the `go test -bench` format, see utils.WriteBenchmarks.
+	// Errors are only kept as the errors/op metric.
+	Benchmarks string
+	Digests    []db.Digest
+	Plans      []db.Plan
+	Footprint  *db.Footprint
+	Variables  map[string]string
+	// Rows in the strategy's table once the cell was done.
+	Table     string
+	TableRows int64
+}
+
+// Opens the session stored in filename, or starts a new one if the file does
+// not exist. A new session picks a seed for the runner unless it already has
+// one, so that resumed cells generate the same data. config holds whatever
+// else the results depend on that the runner does not know about, e.g. the
+// storage, server profile or faults used; it is stored along with the
+// runner's own settings. Resuming fails if any of them changed, or if the
+// runner has a seed other than the stored one; a runner without a seed gets
+// the stored one. The tables written by finished cells are checked to still
+// hold the rows they were left with.
+func OpenSession(ctx context.Context, filename string, r *Runner, config map[string]string) (*Session, error) {
+	s := &Session{Runner: r, Filename: filename}
+	settings := r.settings()
+	maps.Copy(settings, config)
+
+	b, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		if r.Seed == 0 {
+			r.Seed = time.Now().UnixNano()
+		}
+		s.state = sessionState{Seed: r.Seed, Config: settings}
+		return s, s.save()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, &s.state); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	if err := compareConfig(s.state.Config, settings); err != nil {
+		return nil, fmt.Errorf("%s: %w; remove it to start over", filename, err)
+	}
+	if r.Seed != 0 && r.Seed != s.state.Seed {
+		return nil, fmt.Errorf("%s: was started with seed %d, not %d; remove it to start over",
+			filename, s.state.Seed, r.Seed)
+	}
+	r.Seed = s.state.Seed
+	if err := s.verify(ctx); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return s, nil
+}
+
+// Returns the number of cells already done.
+func (s *Session) Done() int {
+	return len(s.state.Cells)
+}
+
+// Runs the workload for every strategy like Runner.Run, skipping strategies
+// that already ran it in this session. Their results are loaded from the state
+// file instead.
+func (s *Session) Run(ctx context.Context, w Workload, strategies ...Strategy) ([]Result, error) {
+	results := []Result{}
+	for _, strategy := range strategies {
+		result, err := s.run(ctx, strategy.Name()+"/"+w.Name, w, strategy)
+		if result.Timer != nil {
+			results = append(results, result)
+		}
+		if err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+// Runs a single cell, or loads it from the state file if it was already done.
+// The result has no timer if ctx was cancelled before the cell started.
+func (s *Session) run(ctx context.Context, key string, w Workload, strategy Strategy) (Result, error) {
+	if cell, ok := s.cell(key); ok {
+		return cell.result()
+	}
+
+	if err := ctx.Err(); err != nil {
+		return Result{}, err
+	}
+	result, err := s.Runner.run(ctx, w, strategy)
+	if err != nil {
+		return result, fmt.Errorf("%s: %w", key, err)
+	}
+	return result, s.checkpoint(ctx, key, strategy, result)
+}
+
+// Deletes the state file, once all the results have been written out.
+func (s *Session) Remove() error {
+	return os.Remove(s.Filename)
+}
+
+func (s *Session) cell(key string) (cellState, bool) {
+	for _, cell := range s.state.Cells {
+		if cell.Key == key {
+			return cell, true
+		}
+	}
+	return cellState{}, false
+}
+
+func (s *Session) checkpoint(ctx context.Context, key string, strategy Strategy, result Result) error {
+	var buf bytes.Buffer
+	if err := utils.WriteBenchmarks(&buf, "", nil, result.Timer); err != nil {
+		return err
+	}
+	rows, err := countRows(ctx, s.Runner.Pool, strategy.Table())
+	if err != nil {
+		return err
+	}
+	s.state.Cells = append(s.state.Cells, cellState{
+		Key:        key,
+		Strategy:   result.Strategy,
+		Workload:   result.Workload,
+		Benchmarks: buf.String(),
+		Digests:    result.Digests,
+		Plans:      result.Plans,
+		Footprint:  result.Footprint,
+		Variables:  result.Variables,
+		Table:      strategy.Table(),
+		TableRows:  rows,
+	})
+	return s.save()
+}
+
+// Writes the state to a temporary file first, so a crash while saving does not
+// lose the previous checkpoint.
+func (s *Session) save() error {
+	b, err := json.MarshalIndent(s.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.Filename + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.Filename)
+}
+
+// Checks that every table is still in the state the last cell that used it
+// left it in. Anything else means the tables were touched in between and the
+// rest of the session would not be comparable.
+func (s *Session) verify(ctx context.Context) error {
+	expected := map[string]int64{}
+	for _, cell := range s.state.Cells {
+		expected[cell.Table] = cell.TableRows
+	}
+	for table, want := range expected {
+		rows, err := countRows(ctx, s.Runner.Pool, table)
+		if err != nil {
+			return err
+		}
+		if rows != want {
+			return fmt.Errorf("table %s has %d rows, expected %d", table, rows, want)
+		}
+	}
+	return nil
+}
+
+func (c cellState) result() (Result, error) {
+	timers, err := utils.ParseBenchmarks(bytes.NewReader([]byte(c.Benchmarks)))
+	if err != nil {
+		return Result{}, fmt.Errorf("%s/%s: %w", c.Strategy, c.Workload, err)
+	}
+	timer := utils.NewTimer(c.Strategy).SetSilent()
+	if len(timers) > 0 {
+		timer = timers[0]
+	}
+	return Result{
+		Strategy:  c.Strategy,
+		Workload:  c.Workload,
+		Timer:     timer,
+		Digests:   c.Digests,
+		Plans:     c.Plans,
+		Footprint: c.Footprint,
+		Variables: c.Variables,
+	}, nil
+}
+
+func countRows(ctx context.Context, pool *sql.DB, table string) (int64, error) {
+	var rows int64
+	err := pool.QueryRowContext(ctx, `SELECT COUNT(*) FROM `+table).Scan(&rows)
+	return rows, err
+}
+
+// Returns an error naming the first setting, by name, that is not the same in
+// both.
+func compareConfig(started, now map[string]string) error {
+	keys := slices.Collect(maps.Keys(started))
+	for key := range now {
+		if _, ok := started[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if started[key] != now[key] {
+			return fmt.Errorf("was started with %s %q, not %q", key, started[key], now[key])
+		}
+	}
+	return nil
+}
+
+// The runner's settings that change results, for OpenSession. Rows and Workers
+// are overridden per cell by RunMatrix, whose cell names include them.
+func (r *Runner) settings() map[string]string {
+	profile := make([]string, len(r.Profile.Iterations))
+	for i, iteration := range r.Profile.Iterations {
+		profile[i] = strconv.Itoa(iteration)
+	}
+	return map[string]string{
+		"iterations":    strconv.Itoa(r.Iterations),
+		"rows":          strconv.Itoa(r.Rows),
+		"workers":       strconv.Itoa(max(r.Workers, 1)),
+		"profile":       strings.Join(profile, ","),
+		"error-policy":  strconv.Itoa(int(r.ErrorPolicy)),
+		"retries":       strconv.Itoa(r.Retries),
+		"server-status": strconv.FormatBool(r.ServerStatus),
+		"proxy":         strconv.FormatBool(r.Proxy != nil),
+	}
+}
@@ -0,0 +1,64 @@
+package bench_test
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/podocarp/mysql-test-test/bench"
+)
+
+func TestSeededDataset(t *testing.T) {
+	a := bench.SeededDataset(10, 42)
+	b := bench.SeededDataset(10, 42)
+	for i := range a {
+		if !slices.Equal(a[i], b[i]) {
+			t.Fatalf("Row %d differs: %v and %v", i, a[i], b[i])
+		}
+	}
+}
+
+func TestOpenSession(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "state.json")
+	ctx := context.Background()
+
+	first := &bench.Runner{Iterations: 3, Rows: 10}
+	config := map[string]string{"storage": "innodb"}
+	if _, err := bench.OpenSession(ctx, filename, first, config); err != nil {
+		t.Fatal(err)
+	}
+	if first.Seed == 0 {
+		t.Fatalf("Expected a new session to pick a seed")
+	}
+
+	// Nothing was done yet, so resuming does not need the database.
+	second := &bench.Runner{Iterations: 3, Rows: 10}
+	s, err := bench.OpenSession(ctx, filename, second, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Seed != first.Seed || s.Done() != 0 {
+		t.Fatalf("Expected the stored seed %d, obtained %d", first.Seed, second.Seed)
+	}
+	same := &bench.Runner{Iterations: 3, Rows: 10, Seed: first.Seed}
+	if _, err := bench.OpenSession(ctx, filename, same, config); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := bench.OpenSession(ctx, filename, &bench.Runner{Iterations: 3, Rows: 10, Seed: first.Seed + 1}, config); err == nil {
+		t.Fatalf("Expected resuming with a different seed to fail")
+	}
+	if _, err := bench.OpenSession(ctx, filename, &bench.Runner{Iterations: 4, Rows: 10}, config); err == nil {
+		t.Fatalf("Expected resuming with different settings to fail")
+	}
+	if _, err := bench.OpenSession(ctx, filename, &bench.Runner{Iterations: 3, Rows: 10, Workers: 4}, config); err == nil {
+		t.Fatalf("Expected resuming with more workers to fail")
+	}
+	if _, err := bench.OpenSession(ctx, filename, &bench.Runner{Iterations: 3, Rows: 10}, map[string]string{"storage": "myisam"}); err == nil {
+		t.Fatalf("Expected resuming with a different storage to fail")
+	}
+	if _, err := bench.OpenSession(ctx, filename, &bench.Runner{Iterations: 3, Rows: 10}, nil); err == nil {
+		t.Fatalf("Expected resuming without the storage to fail")
+	}
+}
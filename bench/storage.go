@@ -0,0 +1,184 @@
+package bench
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Engines a strategy's table can use. MEMORY cannot store JSON or BLOB
+// columns.
+var Engines = []string{"InnoDB", "MyISAM", "MEMORY"}
+
+// InnoDB row formats. COMPRESSED takes a KEY_BLOCK_SIZE.
+var RowFormats = []string{"DYNAMIC", "COMPACT", "REDUNDANT", "COMPRESSED"}
+
+// InnoDB page compression algorithms. Needs a file system that supports hole
+// punching, otherwise the server only warns and stores pages as is.
+var Compressions = []string{"zlib", "lz4"}
+
+// Table options a strategy's table is created with. The zero value is an
+// InnoDB table with the server's default row format and no compression.
+type Storage struct {
+	// InnoDB if empty.
+	Engine string
+	// InnoDB only, the server's default (DYNAMIC) if empty.
+	RowFormat string
+	// Compressed page size in kB for ROW_FORMAT=COMPRESSED: 1, 2, 4, 8 or
+	// 16. 0 for the default, 8.
+	KeyBlockSize int
+	// InnoDB page compression, none if empty.
+	Compression string
+}
+
+// Strategies that support storage options implement this, see WithStorage.
+type storager interface {
+	withStorage(storage Storage) Strategy
+	// Whether the strategy's columns can be stored by the engine.
+	supportsEngine(engine string) bool
+}
+
+// Returns a copy of the strategy whose table uses the given storage options.
+func WithStorage(s Strategy, storage Storage) (Strategy, error) {
+	if err := storage.Validate(); err != nil {
+		return nil, err
+	}
+	st, ok := s.(storager)
+	if !ok {
+		return nil, fmt.Errorf("strategy %s does not support storage options", s.Name())
+	}
+	if !st.supportsEngine(storage.engine()) {
+		return nil, fmt.Errorf("strategy %s cannot be stored in %s", s.Name(), storage.engine())
+	}
+	return st.withStorage(storage), nil
+}
+
+func (s Storage) engine() string {
+	if s.Engine == "" {
+		return "InnoDB"
+	}
+	return s.Engine
+}
+
+// Checks that the options are known and go together.
+func (s Storage) Validate() error {
+	engine := s.engine()
+	if !slices.ContainsFunc(Engines, func(e string) bool { return strings.EqualFold(e, engine) }) {
+		return fmt.Errorf("unknown engine %q", s.Engine)
+	}
+	innodb := strings.EqualFold(engine, "InnoDB")
+	if s.RowFormat != "" && !slices.Contains(RowFormats, strings.ToUpper(s.RowFormat)) {
+		return fmt.Errorf("unknown row format %q", s.RowFormat)
+	}
+	if s.Compression != "" && !slices.Contains(Compressions, strings.ToLower(s.Compression)) {
+		return fmt.Errorf("unknown compression %q", s.Compression)
+	}
+	if !innodb && (s.RowFormat != "" || s.KeyBlockSize != 0 || s.Compression != "") {
+		return fmt.Errorf("row format, key block size and compression need InnoDB")
+	}
+	compressed := strings.EqualFold(s.RowFormat, "COMPRESSED")
+	if s.KeyBlockSize != 0 && (!compressed || !slices.Contains([]int{1, 2, 4, 8, 16}, s.KeyBlockSize)) {
+		return fmt.Errorf("key block size %d needs ROW_FORMAT=COMPRESSED and one of 1, 2, 4, 8 or 16", s.KeyBlockSize)
+	}
+	if compressed && s.Compression != "" {
+		return fmt.Errorf("page compression does not work with ROW_FORMAT=COMPRESSED")
+	}
+	return nil
+}
+
+// Returns the options for CREATE or ALTER TABLE. Options that are not set
+// are reset to their defaults, so altering a table back to the zero Storage
+// undoes earlier changes. Only InnoDB knows COMPRESSION, so other engines
+// leave it out; see Alter for how it is reset.
+func (s Storage) Options() string {
+	rowFormat := "DEFAULT"
+	if s.RowFormat != "" {
+		rowFormat = strings.ToUpper(s.RowFormat)
+	}
+	options := []string{
+		"ENGINE=" + s.engine(),
+		"ROW_FORMAT=" + rowFormat,
+		"KEY_BLOCK_SIZE=" + strconv.Itoa(s.KeyBlockSize),
+	}
+	if !strings.EqualFold(s.engine(), "InnoDB") {
+		return strings.Join(options, " ")
+	}
+	compression := "None"
+	if s.Compression != "" {
+		compression = strings.ToLower(s.Compression)
+	}
+	options = append(options, "COMPRESSION='"+compression+"'")
+	return strings.Join(options, " ")
+}
+
+// Returns the statements that give a table these options, whatever it had
+// before. Moving to another engine first resets the table to plain InnoDB,
+// as the COMPRESSION attribute would otherwise stay behind.
+func (s Storage) Alter(table string) []string {
+	statements := []string{}
+	if !strings.EqualFold(s.engine(), "InnoDB") {
+		statements = append(statements, `ALTER TABLE `+table+` `+Storage{}.Options())
+	}
+	return append(statements, `ALTER TABLE `+table+` `+s.Options())
+}
+
+// Names the options, e.g. innodb, myisam, innodb-compressed-8 or
+// innodb-dynamic-zlib. ParseStorage reads them back.
+func (s Storage) Name() string {
+	parts := []string{strings.ToLower(s.engine())}
+	if s.RowFormat != "" {
+		parts = append(parts, strings.ToLower(s.RowFormat))
+	}
+	if s.KeyBlockSize != 0 {
+		parts = append(parts, strconv.Itoa(s.KeyBlockSize))
+	}
+	if s.Compression != "" {
+		parts = append(parts, strings.ToLower(s.Compression))
+	}
+	return strings.Join(parts, "-")
+}
+
+// Parses a name as returned by Storage.Name. The parts after the engine can
+// come in any order.
+func ParseStorage(name string) (Storage, error) {
+	parts := strings.Split(name, "-")
+	var s Storage
+	for _, engine := range Engines {
+		if strings.EqualFold(engine, parts[0]) {
+			s.Engine = engine
+		}
+	}
+	if s.Engine == "" {
+		return s, fmt.Errorf("%s: unknown engine %q", name, parts[0])
+	}
+	for _, part := range parts[1:] {
+		if n, err := strconv.Atoi(part); err == nil {
+			s.KeyBlockSize = n
+		} else if slices.Contains(RowFormats, strings.ToUpper(part)) {
+			s.RowFormat = strings.ToUpper(part)
+		} else if slices.Contains(Compressions, strings.ToLower(part)) {
+			s.Compression = strings.ToLower(part)
+		} else {
+			return s, fmt.Errorf("%s: unknown option %q", name, part)
+		}
+	}
+	if s.Engine == "InnoDB" {
+		// Keep the zero value for the default so names round trip.
+		s.Engine = ""
+	}
+	return s, s.Validate()
+}
+
+// Applies the storage options to a table. Cheap right after a TRUNCATE, as
+// the rebuilt table is empty.
+func alterStorage(ctx context.Context, pool *sql.DB, table string, storage Storage) error {
+	for _, statement := range storage.Alter(table) {
+		if _, err := pool.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
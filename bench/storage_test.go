@@ -0,0 +1,83 @@
+package bench_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/podocarp/mysql-test-test/bench"
+)
+
+func TestParseStorage(t *testing.T) {
+	cases := []struct {
+		name    string
+		storage bench.Storage
+		options string
+	}{
+		{"innodb", bench.Storage{}, "ENGINE=InnoDB ROW_FORMAT=DEFAULT KEY_BLOCK_SIZE=0 COMPRESSION='None'"},
+		{"myisam", bench.Storage{Engine: "MyISAM"}, "ENGINE=MyISAM ROW_FORMAT=DEFAULT KEY_BLOCK_SIZE=0"},
+		{"memory", bench.Storage{Engine: "MEMORY"}, "ENGINE=MEMORY ROW_FORMAT=DEFAULT KEY_BLOCK_SIZE=0"},
+		{"innodb-compact", bench.Storage{RowFormat: "COMPACT"}, "ENGINE=InnoDB ROW_FORMAT=COMPACT KEY_BLOCK_SIZE=0 COMPRESSION='None'"},
+		{"innodb-compressed-8", bench.Storage{RowFormat: "COMPRESSED", KeyBlockSize: 8}, "ENGINE=InnoDB ROW_FORMAT=COMPRESSED KEY_BLOCK_SIZE=8 COMPRESSION='None'"},
+		{"innodb-dynamic-lz4", bench.Storage{RowFormat: "DYNAMIC", Compression: "lz4"}, "ENGINE=InnoDB ROW_FORMAT=DYNAMIC KEY_BLOCK_SIZE=0 COMPRESSION='lz4'"},
+	}
+	for _, c := range cases {
+		storage, err := bench.ParseStorage(c.name)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if storage != c.storage {
+			t.Errorf("%s: expected %+v, obtained %+v", c.name, c.storage, storage)
+		}
+		if name := storage.Name(); name != c.name {
+			t.Errorf("%s: name does not round trip, obtained %s", c.name, name)
+		}
+		if options := storage.Options(); options != c.options {
+			t.Errorf("%s: expected options %s, obtained %s", c.name, c.options, options)
+		}
+	}
+
+	for _, name := range []string{"aria", "myisam-compact", "innodb-8", "innodb-compact-8", "innodb-compressed-3", "innodb-compressed-zlib", "innodb-fast"} {
+		if _, err := bench.ParseStorage(name); err == nil {
+			t.Errorf("Expected %s to be invalid", name)
+		}
+	}
+}
+
+// Going from compressed InnoDB to MyISAM must not carry any of the InnoDB
+// options over.
+func TestStorageAlter(t *testing.T) {
+	compressed, err := bench.ParseStorage("innodb-compressed-8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	myisam, err := bench.ParseStorage("myisam")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if statements := compressed.Alter("t"); len(statements) != 1 {
+		t.Fatalf("Expected a single statement within InnoDB, obtained %v", statements)
+	}
+	expected := []string{
+		"ALTER TABLE t ENGINE=InnoDB ROW_FORMAT=DEFAULT KEY_BLOCK_SIZE=0 COMPRESSION='None'",
+		"ALTER TABLE t ENGINE=MyISAM ROW_FORMAT=DEFAULT KEY_BLOCK_SIZE=0",
+	}
+	statements := myisam.Alter("t")
+	if !slices.Equal(statements, expected) {
+		t.Fatalf("Expected %q, obtained %q", expected, statements)
+	}
+}
+
+func TestWithStorage(t *testing.T) {
+	memory := bench.Storage{Engine: "MEMORY"}
+	if _, err := bench.WithStorage(bench.JSON{}, memory); err == nil {
+		t.Fatal("Expected JSON columns not to fit in a MEMORY table")
+	}
+	s, err := bench.WithStorage(bench.Bitset{}, memory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.(bench.Bitset).Storage != memory {
+		t.Fatalf("Expected the storage to be set, obtained %+v", s)
+	}
+}
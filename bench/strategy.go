@@ -0,0 +1,84 @@
+package bench
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/podocarp/mysql-test-test/db/migrate"
+	"github.com/podocarp/mysql-test-test/utils"
+)
+
+// A Strategy is one way of storing a set of countries in a table.
+type Strategy interface {
+	// Short name used in timer and benchmark names, e.g. "json".
+	Name() string
+	// The table the strategy stores its rows in.
+	Table() string
+	// Migrations that create and change the table, named after the strategy.
+	Schema() migrate.Set
+	// Migrates the table to the latest version and empties it, so that the
+	// next Write assigns ids starting from 1.
+	Reset(ctx context.Context, pool *sql.DB) error
+	// Inserts one row per element of data.
+	Write(ctx context.Context, pool *sql.DB, data []utils.Countries) error
+	// Reads back the n rows after id offset, i.e. ids offset+1 to offset+n.
+	Read(ctx context.Context, pool *sql.DB, offset, n int) error
+	// Returns the distinct statements run for the named workload, with
+	// example arguments, so their plans can be captured.
+	Queries(workload string) []Query
+}
+
+// A statement and arguments to fill in its placeholders.
+type Query struct {
+	SQL  string
+	Args []any
+}
+
+var strategies = []Strategy{
+	JSON{},
+	Bitset{},
+	Junk{},
+}
+
+// Returns all known strategies.
+func Strategies() []Strategy {
+	return strategies
+}
+
+// Looks up a strategy by its name.
+func StrategyByName(name string) (Strategy, bool) {
+	for _, s := range strategies {
+		if s.Name() == name {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// A row of any of the countries tables.
+type Row struct {
+	ID        uint64
+	Countries *utils.Countries
+}
+
+// Migrates a strategy's table, truncates it and applies the storage options.
+func resetTable(ctx context.Context, pool *sql.DB, s Strategy, storage Storage) error {
+	if _, err := migrate.Up(ctx, pool, s.Schema()); err != nil {
+		return err
+	}
+	if _, err := pool.ExecContext(ctx, `TRUNCATE TABLE `+s.Table()); err != nil {
+		return err
+	}
+	return alterStorage(ctx, pool, s.Table(), storage)
+}
+
+// Creates a strategy's table. Uses IF NOT EXISTS so that tables created
+// before migrations were tracked are adopted rather than failing.
+func createTable(table, ddl string) migrate.Migration {
+	return migrate.Migration{
+		Version: 1,
+		Name:    "create " + table,
+		Up:      []string{ddl},
+		Down:    []string{`DROP TABLE IF EXISTS ` + table},
+	}
+}
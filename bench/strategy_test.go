@@ -0,0 +1,22 @@
+package bench_test
+
+import (
+	"testing"
+
+	"github.com/podocarp/mysql-test-test/bench"
+)
+
+func TestSchemas(t *testing.T) {
+	for _, s := range bench.Strategies() {
+		schema := s.Schema()
+		if schema.Name != s.Name() {
+			t.Errorf("Expected the schema of %s to be named after it, obtained %s", s.Name(), schema.Name)
+		}
+		if err := schema.Validate(); err != nil {
+			t.Error(err)
+		}
+		if schema.Latest() == 0 {
+			t.Errorf("Expected %s to have migrations", s.Name())
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package bench
+
+import (
+	"context"
+	"database/sql"
+	"math/rand"
+
+	"github.com/podocarp/mysql-test-test/utils"
+)
+
+// A Workload is something done to a strategy's table that we want to time.
+type Workload struct {
+	Name string
+	// Runs before every iteration and is not timed.
+	Prepare func(ctx context.Context, pool *sql.DB, s Strategy, data []utils.Countries) error
+	// The part that is timed. data is the rows after id offset of what
+	// Prepare was given; it is all of it unless the run is split across
+	// workers.
+	Run func(ctx context.Context, pool *sql.DB, s Strategy, offset int, data []utils.Countries) error
+}
+
+// Inserts the data into an empty table.
+var Write = Workload{
+	Name: "write",
+	Prepare: func(ctx context.Context, pool *sql.DB, s Strategy, data []utils.Countries) error {
+		return s.Reset(ctx, pool)
+	},
+	Run: func(ctx context.Context, pool *sql.DB, s Strategy, offset int, data []utils.Countries) error {
+		return s.Write(ctx, pool, data)
+	},
+}
+
+// Reads every row back by primary key.
+var Read = Workload{
+	Name: "read",
+	Prepare: func(ctx context.Context, pool *sql.DB, s Strategy, data []utils.Countries) error {
+		if err := s.Reset(ctx, pool); err != nil {
+			return err
+		}
+		return s.Write(ctx, pool, data)
+	},
+	Run: func(ctx context.Context, pool *sql.DB, s Strategy, offset int, data []utils.Countries) error {
+		return s.Read(ctx, pool, offset, len(data))
+	},
+}
+
+var workloads = []Workload{
+	Write,
+	Read,
+}
+
+// Returns all known workloads.
+func Workloads() []Workload {
+	return workloads
+}
+
+// Looks up a workload by its name.
+func WorkloadByName(name string) (Workload, bool) {
+	for _, w := range workloads {
+		if w.Name == name {
+			return w, true
+		}
+	}
+	return Workload{}, false
+}
+
+// Generates rows worth of random country sets.
+func Dataset(rows int) []utils.Countries {
+	data := make([]utils.Countries, rows)
+	for i := range rows {
+		data[i] = utils.RandomCountries()
+	}
+	return data
+}
+
+// Like Dataset, but the same seed always generates the same data.
+func SeededDataset(rows int, seed int64) []utils.Countries {
+	r := rand.New(rand.NewSource(seed))
+	data := make([]utils.Countries, rows)
+	for i := range rows {
+		data[i] = utils.RandomCountriesFrom(r)
+	}
+	return data
+}
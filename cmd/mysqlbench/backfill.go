@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/podocarp/mysql-test-test/bench"
+	"github.com/podocarp/mysql-test-test/db"
+	"github.com/podocarp/mysql-test-test/utils"
+)
+
+// Name of the backfill checkpoint in the output directory.
+const backfillStateFile = "backfill.state.json"
+
+// Converts the JSON table into bitsets, checks a conversion, or times one
+// against a live write workload.
+func backfillCommand(args []string) error {
+	fs := newFlagSet("backfill", "run|verify|bench")
+	addr := fs.String("addr", db.ServerAddr, "address of the mysql server")
+	source := fs.String("source", "countries_json.countries", "table.column holding the JSON arrays")
+	target := fs.String("target", "countries_bitset.countries", "table.column the bitsets are written to, keyed by the source's id")
+	chunk := fs.Int("chunk", 1000, "rows converted per statement")
+	rate := fs.Float64("rate", 0, "rows per second to stay under, 0 for no limit")
+	maxThreads := fs.Int64("max-threads-running", 0, "pause while more threads than this run statements, 0 to never pause")
+	maxHistory := fs.Int64("max-history", 0, "pause while the InnoDB history list is longer than this, 0 to never pause")
+	checkpoint := fs.String("checkpoint", "", "file the last converted id is written to and resumed from, <out>/"+backfillStateFile+" if empty")
+	iterations := fs.Int("iterations", 10, "iterations of bench")
+	rows := fs.Int("rows", 10000, "rows in the table before each bench iteration, and rows written while it runs")
+	out := fs.String("out", defaultOut, "directory the checkpoint and bench's backfill.txt are written to")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("need one of run, verify or bench")
+	}
+
+	if *checkpoint == "" {
+		*checkpoint = filepath.Join(*out, backfillStateFile)
+		if err := os.MkdirAll(*out, 0o755); err != nil {
+			return err
+		}
+	}
+	b := bench.Backfill{
+		ChunkSize:  *chunk,
+		Rate:       *rate,
+		Checkpoint: *checkpoint,
+	}
+	var ok bool
+	if b.Source, b.SourceColumn, ok = strings.Cut(*source, "."); !ok {
+		return fmt.Errorf("-source: %q is not table.column", *source)
+	}
+	if b.Target, b.TargetColumn, ok = strings.Cut(*target, "."); !ok {
+		return fmt.Errorf("-target: %q is not table.column", *target)
+	}
+	switch {
+	case *maxThreads > 0 && *maxHistory > 0:
+		threads, history := bench.ThreadsRunningAbove(*maxThreads), bench.HistoryLengthAbove(*maxHistory)
+		b.Throttle = func(pool *sql.DB) (bool, error) {
+			busy, err := threads(pool)
+			if busy || err != nil {
+				return busy, err
+			}
+			return history(pool)
+		}
+	case *maxThreads > 0:
+		b.Throttle = bench.ThreadsRunningAbove(*maxThreads)
+	case *maxHistory > 0:
+		b.Throttle = bench.HistoryLengthAbove(*maxHistory)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	pool, err := db.OpenAt(*addr)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	switch fs.Arg(0) {
+	case "run":
+		state, err := b.Run(ctx, pool)
+		fmt.Printf("Converted %d rows up to id %d, throttled for %s\n", state.Rows, state.LastID, state.Throttled)
+		if err != nil {
+			fmt.Println("Run again to resume from", *checkpoint)
+		}
+		return err
+	case "verify":
+		check, err := b.Verify(ctx, pool)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Checked %d rows: %d missing, %d mismatched\n", check.Rows, check.Missing, check.Mismatched)
+		if check.Missing+check.Mismatched > 0 {
+			return fmt.Errorf("backfill incomplete, e.g. ids %v", check.IDs)
+		}
+		return nil
+	case "bench":
+		return benchBackfill(ctx, pool, b, *iterations, *rows, *out)
+	}
+	fs.Usage()
+	return fmt.Errorf("unknown action %q", fs.Arg(0))
+}
+
+// Times the backfill of the json strategy's table while it is written to.
+func benchBackfill(ctx context.Context, pool *sql.DB, b bench.Backfill, iterations, rows int, out string) error {
+	runner := bench.Runner{
+		Pool:         pool,
+		Iterations:   iterations,
+		Rows:         rows,
+		ServerStatus: true,
+		Footprint:    true,
+	}
+	w := bench.BackfillWorkload(b)
+	results, err := runner.Run(ctx, w, bench.JSON{})
+	for _, result := range results {
+		result.Timer.Echo()
+	}
+	if len(results) > 0 {
+		if err := os.MkdirAll(out, 0o755); err != nil {
+			return err
+		}
+		config := map[string]string{
+			"iterations": strconv.Itoa(iterations),
+			"rows":       strconv.Itoa(rows),
+			"chunk":      strconv.Itoa(b.ChunkSize),
+		}
+		if bench.Partial(results) {
+			config["partial"] = "true"
+		}
+		utils.SaveBenchmarks(filepath.Join(out, "backfill.txt"), "Backfill", config, bench.Timers(results)...)
+	}
+	return err
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/podocarp/mysql-test-test/bench"
+	"github.com/podocarp/mysql-test-test/db"
+	"github.com/podocarp/mysql-test-test/db/migrate"
+)
+
+// Migrates the tables of the strategies down, which drops them, and with
+// -files deletes the files run, matrix and backfill write to their output
+// directory. Nothing else in it is touched.
+func cleanCommand(args []string) error {
+	fs := newFlagSet("clean", "")
+	addr := fs.String("addr", db.ServerAddr, "address of the mysql server")
+	files := fs.Bool("files", false, "also delete reports, benchmarks, profiles and state files")
+	out := fs.String("out", defaultOut, "output directory given to run, matrix and backfill")
+	fs.Parse(args)
+
+	pool, err := db.OpenAt(*addr)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	for _, s := range bench.Strategies() {
+		if _, err := migrate.Down(context.Background(), pool, s.Schema()); err != nil {
+			return err
+		}
+		// Tables from before migrations were tracked are not dropped by
+		// the migrations.
+		if _, err := pool.Exec(`DROP TABLE IF EXISTS ` + s.Table()); err != nil {
+			return err
+		}
+		fmt.Println("Dropped", s.Table())
+	}
+	if !*files {
+		return nil
+	}
+
+	filenames, err := outputFiles(*out)
+	if err != nil {
+		return err
+	}
+	for _, filename := range filenames {
+		err := os.Remove(filename)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		fmt.Println("Removed", filename)
+	}
+	// Directories are only removed once nothing else is left in them.
+	dirs, err := filepath.Glob(filepath.Join(*out, "profiles", "*"))
+	if err != nil {
+		return err
+	}
+	for _, dir := range append(dirs, filepath.Join(*out, "profiles"), *out) {
+		os.Remove(dir)
+	}
+	return nil
+}
+
+// Returns the files run, matrix and backfill may have written to out.
+func outputFiles(out string) ([]string, error) {
+	filenames := []string{}
+	for _, name := range []string{stateFile, matrixStateFile, backfillStateFile} {
+		filenames = append(filenames, filepath.Join(out, name), filepath.Join(out, name+".tmp"))
+	}
+	filenames = append(filenames,
+		filepath.Join(out, "matrix.html"),
+		filepath.Join(out, "matrix.txt"),
+		filepath.Join(out, "backfill.txt"))
+	for _, w := range bench.Workloads() {
+		filenames = append(filenames,
+			filepath.Join(out, w.Name+".html"),
+			filepath.Join(out, w.Name+".txt"))
+		// See utils.ProfileSchedule.
+		profiles, err := filepath.Glob(filepath.Join(out, "profiles", w.Name, "*-*.out"))
+		if err != nil {
+			return nil, err
+		}
+		filenames = append(filenames, profiles...)
+	}
+	return filenames, nil
+}
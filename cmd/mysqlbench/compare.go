@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/podocarp/mysql-test-test/report"
+	"github.com/podocarp/mysql-test-test/utils"
+)
+
+// Prints the mean of every metric of every benchmark, one column per file,
+// with the change relative to the first file. For a proper statistical
+// comparison use benchstat on the same files.
+func compareCommand(args []string) error {
+	fs := newFlagSet("compare", "base.txt other.txt...")
+	fs.Parse(args)
+	if fs.NArg() < 2 {
+		fs.Usage()
+		return errors.New("need at least two files")
+	}
+
+	files := fs.Args()
+	loaded := make([]map[string]*utils.Timer, len(files))
+	names := []string{}
+	for i, filename := range files {
+		loaded[i] = map[string]*utils.Timer{}
+		timers, err := loadBenchmarks(filename)
+		if err != nil {
+			return err
+		}
+		for _, t := range timers {
+			loaded[i][t.Name()] = t
+			if !slices.Contains(names, t.Name()) {
+				names = append(names, t.Name())
+			}
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprint(w, "benchmark\tunit\t")
+	for _, filename := range files {
+		fmt.Fprintf(w, "%s\t", filepath.Base(filename))
+	}
+	for _, filename := range files[1:] {
+		fmt.Fprintf(w, "Δ %s\t", filepath.Base(filename))
+	}
+	fmt.Fprintln(w)
+
+	for _, name := range names {
+		timers := make([]*utils.Timer, len(files))
+		units := []string{}
+		for i := range files {
+			timers[i] = loaded[i][name]
+			if timers[i] == nil {
+				continue
+			}
+			for _, unit := range timers[i].Units() {
+				if !slices.Contains(units, unit) {
+					units = append(units, unit)
+				}
+			}
+		}
+
+		means := func(unit string) []float64 {
+			values := make([]float64, len(timers))
+			for i, t := range timers {
+				if t == nil {
+					values[i] = math.NaN()
+					continue
+				}
+				values[i] = report.MetricMean(t, unit)
+			}
+			return values
+		}
+		for _, unit := range append([]string{"ms/op"}, units...) {
+			values := means(unit)
+			fmt.Fprintf(w, "%s\t%s\t", name, unit)
+			for _, v := range values {
+				fmt.Fprintf(w, "%s\t", report.FormatFloat(v))
+			}
+			for _, v := range values[1:] {
+				fmt.Fprintf(w, "%s\t", formatDelta(values[0], v))
+			}
+			fmt.Fprintln(w)
+		}
+	}
+	return w.Flush()
+}
+
+func formatDelta(base, v float64) string {
+	if math.IsNaN(base) || math.IsNaN(v) || base == 0 {
+		return "-"
+	}
+	return strconv.FormatFloat(100*(v-base)/base, 'f', 1, 64) + "%"
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/podocarp/mysql-test-test/utils"
+)
+
+// Decodes values as stored by the strategies, e.g. copied out of a mysql
+// client: a bitset or versioned binary encoding as hex, with or without a 0x
+// prefix, or a JSON array of country ids.
+func decodeCommand(args []string) error {
+	fs := newFlagSet("decode", "value...")
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("need at least one value")
+	}
+
+	for _, arg := range fs.Args() {
+		countries, err := decode(arg)
+		if err != nil {
+			return fmt.Errorf("%s: %w", arg, err)
+		}
+		fmt.Printf("%s: %d countries\n", arg, len(countries))
+		for _, c := range countries {
+			fmt.Println(" ", c)
+		}
+	}
+	return nil
+}
+
+func decode(s string) (utils.Countries, error) {
+	var countries utils.Countries
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "[") {
+		err := countries.Scan(s)
+		return countries, err
+	}
+
+	b, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X"))
+	if err != nil {
+		return nil, err
+	}
+	err = countries.Scan(b)
+	return countries, err
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/podocarp/mysql-test-test/bench"
+	"github.com/podocarp/mysql-test-test/db"
+)
+
+func listCommand(args []string) error {
+	fs := newFlagSet("list", "")
+	fs.Parse(args)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STRATEGY\tTABLE")
+	for _, s := range bench.Strategies() {
+		fmt.Fprintf(w, "%s\t%s\n", s.Name(), s.Table())
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "WORKLOAD")
+	for _, workload := range bench.Workloads() {
+		fmt.Fprintln(w, workload.Name)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "SERVER PROFILE\tVARIABLES")
+	for _, p := range db.ServerProfiles() {
+		vars := []string{}
+		for _, name := range db.ProfileVariables(p) {
+			vars = append(vars, name+"="+p.Variables[name])
+		}
+		fmt.Fprintf(w, "%s\t%s\n", p.Name, strings.Join(vars, " "))
+	}
+	return w.Flush()
+}
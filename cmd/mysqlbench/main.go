@@ -0,0 +1,141 @@
+// Command mysqlbench runs and reports on the storage strategy benchmarks.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/podocarp/mysql-test-test/bench"
+	"github.com/podocarp/mysql-test-test/utils"
+)
+
+// Where run, matrix and backfill write their output unless told otherwise. It
+// is ignored by git, and clean only deletes from it what they wrote.
+const defaultOut = "results"
+
+// A subcommand, run with the arguments that follow its name.
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{"run", "Run workloads against strategies and write reports", runCommand},
+	{"matrix", "Run every combination described in a JSON config file", matrixCommand},
+	{"list", "List strategies and workloads", listCommand},
+	{"compare", "Compare benchmark files written by run", compareCommand},
+	{"report", "Build an html report from benchmark files", reportCommand},
+	{"decode", "Decode a stored bitset or JSON array into countries", decodeCommand},
+	{"backfill", "Convert the JSON table into bitsets in chunks, verify or time it", backfillCommand},
+	{"migrate", "Migrate, reset or show the status of the strategies' tables", migrateCommand},
+	{"clean", "Drop the strategies' tables and the files written by run", cleanCommand},
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: mysqlbench <command> [flags] [args]\n\nCommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(out, "  %-8s %s\n", c.name, c.usage)
+	}
+	fmt.Fprintf(out, "\nStrategies: %s\n", strings.Join(strategyNames(bench.Strategies()), ", "))
+	fmt.Fprintf(out, "Workloads: %s\n", strings.Join(workloadNames(bench.Workloads()), ", "))
+	fmt.Fprintf(out, "\nRun mysqlbench <command> -help for the flags of a command.\n")
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := flag.Arg(0)
+	for _, c := range commands {
+		if c.name != name {
+			continue
+		}
+		if err := c.run(flag.Args()[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, "mysqlbench "+name+":", err)
+			os.Exit(1)
+		}
+		return
+	}
+	fmt.Fprintln(os.Stderr, "mysqlbench: unknown command", name)
+	usage()
+	os.Exit(2)
+}
+
+// Returns a flag set for a subcommand whose help also lists the strategies and
+// workloads.
+func newFlagSet(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		out := fs.Output()
+		fmt.Fprintf(out, "Usage: mysqlbench %s [flags] %s\n\nFlags:\n", name, args)
+		fs.PrintDefaults()
+		fmt.Fprintf(out, "\nStrategies: %s\n", strings.Join(strategyNames(bench.Strategies()), ", "))
+		fmt.Fprintf(out, "Workloads: %s\n", strings.Join(workloadNames(bench.Workloads()), ", "))
+	}
+	return fs
+}
+
+func strategyNames(strategies []bench.Strategy) []string {
+	names := make([]string, len(strategies))
+	for i, s := range strategies {
+		names[i] = s.Name()
+	}
+	return names
+}
+
+func workloadNames(workloads []bench.Workload) []string {
+	names := make([]string, len(workloads))
+	for i, w := range workloads {
+		names[i] = w.Name
+	}
+	return names
+}
+
+// Looks up a comma separated list of strategy names.
+func parseStrategies(list string) ([]bench.Strategy, error) {
+	strategies := []bench.Strategy{}
+	for _, name := range strings.Split(list, ",") {
+		s, ok := bench.StrategyByName(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("unknown strategy %q", name)
+		}
+		strategies = append(strategies, s)
+	}
+	return strategies, nil
+}
+
+// Looks up a comma separated list of workload names.
+func parseWorkloads(list string) ([]bench.Workload, error) {
+	workloads := []bench.Workload{}
+	for _, name := range strings.Split(list, ",") {
+		w, ok := bench.WorkloadByName(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("unknown workload %q", name)
+		}
+		workloads = append(workloads, w)
+	}
+	return workloads, nil
+}
+
+// Like utils.LoadBenchmarks, but returns errors instead of panicking.
+func loadBenchmarks(filename string) ([]*utils.Timer, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	timers, err := utils.ParseBenchmarks(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return timers, nil
+}
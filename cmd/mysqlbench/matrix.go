@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+
+	"github.com/podocarp/mysql-test-test/bench"
+	"github.com/podocarp/mysql-test-test/db"
+	"github.com/podocarp/mysql-test-test/report"
+	"github.com/podocarp/mysql-test-test/utils"
+)
+
+// Name of the matrix state file in the output directory, see bench.Session.
+const matrixStateFile = "matrix.state.json"
+
+// Runs every cell of a matrix described in a JSON file, see bench.Config.
+func matrixCommand(args []string) error {
+	fs := newFlagSet("matrix", "config.json")
+	dryRun := fs.Bool("dry-run", false, "only list the cells that would run")
+	out := fs.String("out", defaultOut, "directory the report, benchmarks and state file are written to")
+	addr := fs.String("addr", db.ServerAddr, "address of the mysql server")
+	retries := fs.Int("retries", 3, "times a failed iteration is retried before it is recorded as an error")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("need a config file")
+	}
+
+	config, err := bench.LoadConfig(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	cells, err := config.Cells()
+	if err != nil {
+		return err
+	}
+	if config.Iterations == 0 {
+		config.Iterations = 30
+	}
+	if *dryRun {
+		for i, cell := range cells {
+			fmt.Printf("%4d  %s\n", i+1, cell.Name())
+		}
+		fmt.Printf("%d cells of %d iterations\n", len(cells), config.Iterations)
+		return nil
+	}
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	pool, err := db.OpenAt(*addr)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	runner := bench.Runner{
+		Pool:         pool,
+		Iterations:   config.Iterations,
+		Seed:         config.Seed,
+		ServerStatus: true,
+		Footprint:    true,
+		ErrorPolicy:  utils.ErrorRetry,
+		Retries:      *retries,
+		Variables:    config.ServerVariables(),
+	}
+	state := filepath.Join(*out, matrixStateFile)
+	// Cell names cover the dimensions, but not what the pools and servers
+	// they name are, or the pivots.
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	session, err := bench.OpenSession(ctx, state, &runner, map[string]string{"config": string(configJSON)})
+	if err != nil {
+		return err
+	}
+	if session.Done() > 0 {
+		fmt.Printf("Resuming %s, %d cells already done\n", state, session.Done())
+	}
+
+	results, err := session.RunMatrix(ctx, config, cells)
+	if err != nil {
+		fmt.Println("Run aborted:", err)
+		fmt.Println("Run again to resume from", state)
+	}
+	writeMatrixReport(filepath.Join(*out, "matrix.html"), config, results, err != nil)
+
+	timers := make([]*utils.Timer, len(results))
+	for i, result := range results {
+		timers[i] = result.Result.Timer
+	}
+	benchConfig := map[string]string{
+		"iterations": strconv.Itoa(config.Iterations),
+		"seed":       strconv.FormatInt(runner.Seed, 10),
+	}
+	if err != nil {
+		benchConfig["partial"] = "true"
+	}
+	utils.SaveBenchmarks(filepath.Join(*out, "matrix.txt"), "Matrix", benchConfig, timers...)
+
+	if err == nil {
+		return session.Remove()
+	}
+	return nil
+}
+
+func writeMatrixReport(filename string, config bench.Config, results []bench.CellResult, partial bool) {
+	r := report.New("Benchmark matrix")
+	r.Partial = partial
+
+	pivots := config.Pivots
+	if len(pivots) == 0 {
+		pivots = []bench.Pivot{{Rows: "workload", Cols: "strategy", Metric: "ms/op"}}
+	}
+	for _, p := range pivots {
+		values := make([]report.PivotValue, len(results))
+		for i, result := range results {
+			values[i] = report.PivotValue{
+				Row:   result.Cell.Get(p.Rows),
+				Col:   result.Cell.Get(p.Cols),
+				Value: result.Result.Value(p.Metric),
+			}
+		}
+		r.AddTable(report.PivotTable(fmt.Sprintf("%s by %s and %s", p.Metric, p.Rows, p.Cols), p.Rows, p.Cols, values...))
+	}
+
+	timers := make([]*utils.Timer, len(results))
+	footprints := []db.Footprint{}
+	for i, result := range results {
+		timers[i] = result.Result.Timer
+		if result.Result.Footprint != nil {
+			footprint := *result.Result.Footprint
+			footprint.Table = result.Cell.Name()
+			footprints = append(footprints, footprint)
+		}
+	}
+	r.AddChart(utils.LineGraph("Time taken per cell", timers...))
+	columns := make([]report.VariableColumn, len(results))
+	for i, result := range results {
+		profile, _ := config.ServerProfile(result.Cell.Server)
+		columns[i] = report.VariableColumn{
+			Name:   result.Cell.Name(),
+			Values: result.Result.Variables,
+			Want:   profile.Variables,
+		}
+	}
+	r.AddTable(report.VariableTable("Server variables", columns...))
+	r.AddTable(report.FootprintTable("Storage footprint", footprints...))
+	r.Save(filename)
+}
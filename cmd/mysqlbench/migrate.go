@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/podocarp/mysql-test-test/bench"
+	"github.com/podocarp/mysql-test-test/db"
+	"github.com/podocarp/mysql-test-test/db/migrate"
+)
+
+// Migrates the strategies' tables up or down, recreates them or shows which
+// migrations were applied.
+func migrateCommand(args []string) error {
+	fs := newFlagSet("migrate", "up|down|reset|status")
+	addr := fs.String("addr", db.ServerAddr, "address of the mysql server")
+	strategyList := fs.String("strategies", strings.Join(strategyNames(bench.Strategies()), ","), "comma separated strategies whose tables to migrate")
+	to := fs.Int("to", -1, "version up or down migrate to, the latest for up and 0 for down if not set")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("need one of up, down, reset or status")
+	}
+	action := fs.Arg(0)
+
+	strategies, err := parseStrategies(*strategyList)
+	if err != nil {
+		return err
+	}
+	pool, err := db.OpenAt(*addr)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+	ctx := context.Background()
+
+	if action == "status" {
+		return migrateStatus(ctx, pool, strategies)
+	}
+	for _, s := range strategies {
+		schema := s.Schema()
+		version := *to
+		var applied []migrate.Migration
+		switch action {
+		case "up":
+			if version < 0 {
+				version = schema.Latest()
+			}
+			applied, err = migrate.UpTo(ctx, pool, schema, version)
+		case "down":
+			applied, err = migrate.DownTo(ctx, pool, schema, max(version, 0))
+		case "reset":
+			err = migrate.Reset(ctx, pool, schema)
+		default:
+			fs.Usage()
+			return fmt.Errorf("unknown action %q", action)
+		}
+		for _, m := range applied {
+			fmt.Printf("%s: %s %d %s\n", schema.Name, action, m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if action == "reset" {
+			fmt.Printf("%s: reset to %d\n", schema.Name, schema.Latest())
+		}
+	}
+	return nil
+}
+
+func migrateStatus(ctx context.Context, pool *sql.DB, strategies []bench.Strategy) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SCHEMA\tVERSION\tNAME\tAPPLIED")
+	for _, s := range strategies {
+		states, err := migrate.Status(ctx, pool, s.Schema())
+		if err != nil {
+			return err
+		}
+		for _, state := range states {
+			applied := "-"
+			if state.Applied {
+				applied = state.AppliedAt.Format(time.DateTime)
+			}
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", s.Schema().Name, state.Version, state.Name, applied)
+		}
+	}
+	return w.Flush()
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/podocarp/mysql-test-test/report"
+	"github.com/podocarp/mysql-test-test/utils"
+)
+
+// Builds a report from benchmark files, e.g. written by run or by `go test
+// -bench`. Benchmarks of the same name in different files are kept apart by
+// prefixing them with the file name.
+func reportCommand(args []string) error {
+	fs := newFlagSet("report", "file.txt...")
+	out := fs.String("o", "report.html", "html file to write")
+	title := fs.String("title", "Benchmarks", "title of the report")
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("need at least one file")
+	}
+
+	all := []*utils.Timer{}
+	for _, filename := range fs.Args() {
+		timers, err := loadBenchmarks(filename)
+		if err != nil {
+			return err
+		}
+		if fs.NArg() > 1 {
+			base := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+			for i, t := range timers {
+				timers[i] = t.SetName(base + "/" + t.Name())
+			}
+		}
+		all = append(all, timers...)
+	}
+
+	units := []string{}
+	for _, t := range all {
+		for _, unit := range t.Units() {
+			if !slices.Contains(units, unit) {
+				units = append(units, unit)
+			}
+		}
+	}
+
+	r := report.New(*title)
+	r.AddChart(utils.LineGraph(*title, all...))
+	for _, unit := range units {
+		if unit != "rows/op" {
+			r.AddChart(utils.MetricGraph(unit, unit, all...))
+		}
+	}
+	r.AddTable(report.MetricTable("Metrics per iteration and per row", units, all...))
+	r.Save(*out)
+	return nil
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/podocarp/mysql-test-test/bench"
+	"github.com/podocarp/mysql-test-test/db"
+	"github.com/podocarp/mysql-test-test/proxy"
+	"github.com/podocarp/mysql-test-test/report"
+	"github.com/podocarp/mysql-test-test/utils"
+)
+
+// Name of the state file in the output directory, see bench.Session.
+const stateFile = "mysqlbench.state.json"
+
+func runCommand(args []string) error {
+	fs := newFlagSet("run", "")
+	iterations := fs.Int("iterations", 30, "iterations per strategy and workload")
+	rows := fs.Int("rows", 1000, "rows generated for each iteration")
+	workers := fs.Int("workers", 1, "goroutines the timed part of each iteration is split across")
+	strategyList := fs.String("strategies", "json,bitset", "comma separated strategies to run")
+	workloadList := fs.String("workloads", "write,read", "comma separated workloads to run")
+	out := fs.String("out", defaultOut, "directory reports, benchmarks, profiles and the state file are written to")
+	seed := fs.Int64("seed", 0, "seed of the generated data, 0 picks one")
+	addr := fs.String("addr", db.ServerAddr, "address of the mysql server")
+	profileIterations := fs.String("profile", "1,11,21", "comma separated iterations to profile instead of time")
+	retries := fs.Int("retries", 3, "times a failed iteration is retried before it is recorded as an error")
+	top := fs.Int("top", 20, "functions listed in the profile summaries")
+	traffic := fs.Bool("traffic", false, "connect through a proxy that records the wire traffic, implied by the fault flags below")
+	latency := fs.Duration("latency", 0, "latency the proxy adds in each direction")
+	jitter := fs.Duration("jitter", 0, "random extra latency the proxy adds, up to this much")
+	bandwidth := fs.Int64("bandwidth", 0, "bytes per second the proxy lets through per connection, 0 for no limit")
+	resetRate := fs.Float64("reset-rate", 0, "probability the proxy resets a connection whenever the client sends something")
+	serverProfile := fs.String("server", "", "server profile to apply before running, see list")
+	persist := fs.Bool("persist", false, "apply the server profile with SET PERSIST, and keep it afterwards")
+	storageName := fs.String("storage", "innodb", "table options of every strategy, e.g. myisam, innodb-compressed-8 or innodb-dynamic-zlib")
+	fs.Parse(args)
+
+	strategies, err := parseStrategies(*strategyList)
+	if err != nil {
+		return err
+	}
+	workloads, err := parseWorkloads(*workloadList)
+	if err != nil {
+		return err
+	}
+	storage, err := bench.ParseStorage(*storageName)
+	if err != nil {
+		return fmt.Errorf("-storage: %w", err)
+	}
+	for i, s := range strategies {
+		if strategies[i], err = bench.WithStorage(s, storage); err != nil {
+			return err
+		}
+	}
+	var profile db.ServerProfile
+	if *serverProfile != "" {
+		var ok bool
+		if profile, ok = db.ServerProfileByName(*serverProfile); !ok {
+			return fmt.Errorf("unknown server profile %q", *serverProfile)
+		}
+	}
+	schedule := utils.ProfileSchedule{Dir: filepath.Join(*out, "profiles")}
+	if *profileIterations != "" {
+		for _, s := range strings.Split(*profileIterations, ",") {
+			i, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil {
+				return fmt.Errorf("-profile: %w", err)
+			}
+			schedule.Iterations = append(schedule.Iterations, i)
+		}
+	}
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		return err
+	}
+
+	// On Ctrl-C the current iteration is cancelled and everything collected
+	// so far is still written out, marked as partial.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// The proxy costs client CPU and allocations, so only go through it
+	// when asked to.
+	faults := proxy.Faults{
+		Latency:   *latency,
+		Jitter:    *jitter,
+		Bandwidth: *bandwidth,
+		ResetRate: *resetRate,
+	}
+	var p *proxy.Proxy
+	serverAddr := *addr
+	if *traffic || faults != (proxy.Faults{}) {
+		if p, err = proxy.Start("127.0.0.1:0", *addr); err != nil {
+			return err
+		}
+		defer p.Close()
+		p.SetFaults(faults)
+		serverAddr = p.Addr()
+	}
+
+	pool, err := db.OpenAt(serverAddr)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	if profile.Name != "" {
+		applied, err := db.ApplyProfile(pool, profile, *persist)
+		if err != nil {
+			return err
+		}
+		for name, value := range applied.NeedsRestart {
+			fmt.Printf("%s is static and stays %s until a restart, the %s profile wants %s\n",
+				name, value, profile.Name, profile.Variables[name])
+		}
+		if !*persist {
+			defer func() {
+				if _, err := db.SetGlobalVariables(pool, applied.Previous); err != nil {
+					fmt.Println("Could not restore server variables:", err)
+				}
+			}()
+		}
+	}
+
+	runner := bench.Runner{
+		Pool:         pool,
+		Proxy:        p,
+		Iterations:   *iterations,
+		Rows:         *rows,
+		Seed:         *seed,
+		Workers:      *workers,
+		ServerStatus: true,
+		Digests:      true,
+		Explain:      true,
+		Footprint:    true,
+		ErrorPolicy:  utils.ErrorRetry,
+		Retries:      *retries,
+		Profile:      schedule,
+		Variables:    db.ProfileVariables(db.ServerProfiles()...),
+	}
+	if pid, err := utils.FindProcess("mysqld"); err == nil {
+		runner.ServerPID = pid
+	} else {
+		fmt.Println("Not recording server CPU:", err)
+	}
+
+	state := filepath.Join(*out, stateFile)
+	// Everything else the results depend on, so a resumed run cannot mix in
+	// results of other settings.
+	config := map[string]string{
+		"storage":    storage.Name(),
+		"server":     profile.Name,
+		"latency":    latency.String(),
+		"jitter":     jitter.String(),
+		"bandwidth":  strconv.FormatInt(*bandwidth, 10),
+		"reset-rate": strconv.FormatFloat(*resetRate, 'g', -1, 64),
+	}
+	session, err := bench.OpenSession(ctx, state, &runner, config)
+	if err != nil {
+		return err
+	}
+	if session.Done() > 0 {
+		fmt.Printf("Resuming %s, %d runs already done\n", state, session.Done())
+	}
+	fmt.Println("Seed:", runner.Seed)
+
+	finished := true
+	for _, w := range workloads {
+		if ctx.Err() != nil {
+			fmt.Println("Interrupted, skipping", w.Name)
+			finished = false
+			continue
+		}
+		results, err := session.Run(ctx, w, strategies...)
+		if err != nil {
+			// Keep whatever was collected before an error or an interrupt,
+			// so it still gets written out.
+			fmt.Println("Run aborted:", err)
+			fmt.Println("Run again to resume from", state)
+			finished = false
+		}
+		for _, result := range results {
+			result.Timer.Echo()
+		}
+
+		title := fmt.Sprintf("%s (%s)", strings.Join(strategyNames(strategies), " vs "), w.Name)
+		writeReport(&runner, w, filepath.Join(*out, w.Name+".html"), title, *top, profile, strategies, results)
+		saveBenchmarks(&runner, filepath.Join(*out, w.Name+".txt"), "Countries/"+w.Name, storage, results)
+	}
+
+	if finished {
+		return session.Remove()
+	}
+	return nil
+}
+
+func writeReport(runner *bench.Runner, w bench.Workload, filename, title string, top int, profile db.ServerProfile, strategies []bench.Strategy, results []bench.Result) {
+	timers := bench.Timers(results)
+	r := report.New(title)
+	r.Partial = bench.Partial(results)
+	r.AddChart(utils.LineGraph(title, timers...))
+	r.AddChart(utils.MetricGraph("Bytes allocated", "B/op", timers...))
+	r.AddChart(utils.MetricGraph("GC pause", "gc-pause-ns/op", timers...))
+	r.AddChart(utils.MetricGraph("Errors", "errors/op", timers...))
+	r.AddTable(report.ErrorTable("Errors", timers...))
+	columns := make([]report.VariableColumn, len(results))
+	for i, result := range results {
+		columns[i] = report.VariableColumn{Name: result.Strategy, Values: result.Variables, Want: profile.Variables}
+	}
+	r.AddTable(report.VariableTable("Server variables", columns...))
+	for _, timer := range timers {
+		r.AddChart(utils.CPUBreakdownGraph("Time breakdown: "+timer.Name(), timer))
+	}
+	r.AddTable(report.MetricTable("Server status per iteration and per row", db.StatusUnits(timers...), timers...))
+	if runner.Proxy != nil {
+		traffic := report.MetricTable("Wire traffic per iteration and per row", proxy.Units(timers...), timers...)
+		traffic.Notes = append(traffic.Notes, "The proxy runs in this process, so its CPU and allocations count as client CPU and allocations above.")
+		r.AddTable(traffic)
+	}
+	footprints := []db.Footprint{}
+	for _, result := range results {
+		if result.Footprint != nil {
+			footprints = append(footprints, *result.Footprint)
+		}
+	}
+	r.AddChart(report.FootprintChart("Storage footprint", footprints...))
+	r.AddTable(report.FootprintTable("Storage footprint", footprints...))
+	for _, result := range results {
+		r.AddTable(report.DigestTable("Statements: "+result.Strategy, result.Digests, result.Timer.Total()))
+		r.AddTable(report.PlanTable("Plans: "+result.Strategy, result.Plans))
+	}
+
+	if len(runner.Profile.Iterations) > 0 {
+		dir := filepath.Join(runner.Profile.Dir, w.Name)
+		err := r.AddProfileSummary(dir, utils.ProfileCPU, top, strategyNames(strategies)...)
+		if err != nil {
+			fmt.Println("Skipping profile summary:", err)
+		}
+	}
+	r.Save(filename)
+}
+
+func saveBenchmarks(runner *bench.Runner, filename, prefix string, storage bench.Storage, results []bench.Result) {
+	config := map[string]string{
+		"storage":    storage.Name(),
+		"iterations": strconv.Itoa(runner.Iterations),
+		"rows":       strconv.Itoa(runner.Rows),
+		"workers":    strconv.Itoa(max(runner.Workers, 1)),
+		"seed":       strconv.FormatInt(runner.Seed, 10),
+	}
+	if bench.Partial(results) {
+		config["partial"] = "true"
+	}
+	// Makes it easy to tell apart files from runs with different server
+	// profiles.
+	if len(results) > 0 {
+		for name, value := range results[0].Variables {
+			config[name] = value
+		}
+	}
+	utils.SaveBenchmarks(filename, prefix, config, bench.Timers(results)...)
+}
@@ -0,0 +1,110 @@
+package db
+
+import (
+	"cmp"
+	"database/sql"
+	"slices"
+	"time"
+)
+
+// Server side statistics of one normalized statement, from
+// performance_schema.events_statements_summary_by_digest.
+type Digest struct {
+	Digest string
+	// The statement with literals replaced by ?.
+	Text            string
+	Count           int64
+	Latency         time.Duration
+	LockTime        time.Duration
+	RowsExamined    int64
+	RowsSent        int64
+	RowsAffected    int64
+	TmpTables       int64
+	TmpDiskTables   int64
+	SortRows        int64
+	SortMergePasses int64
+	SortScans       int64
+	NoIndexUsed     int64
+}
+
+// Clears the statement digest summary, so the next call to Digests only sees
+// statements run after this. Needs performance_schema, which MySQL 8 enables by
+// default.
+func ResetDigests(pool *sql.DB) error {
+	_, err := pool.Exec(`TRUNCATE TABLE performance_schema.events_statements_summary_by_digest`)
+	return err
+}
+
+// Returns the statement digests of the current database, slowest first.
+func Digests(pool *sql.DB) ([]Digest, error) {
+	// Timers are in picoseconds.
+	rows, err := pool.Query(`SELECT
+          DIGEST, COALESCE(DIGEST_TEXT, ''), COUNT_STAR,
+          SUM_TIMER_WAIT DIV 1000, SUM_LOCK_TIME DIV 1000,
+          SUM_ROWS_EXAMINED, SUM_ROWS_SENT, SUM_ROWS_AFFECTED,
+          SUM_CREATED_TMP_TABLES, SUM_CREATED_TMP_DISK_TABLES,
+          SUM_SORT_ROWS, SUM_SORT_MERGE_PASSES, SUM_SORT_SCAN,
+          SUM_NO_INDEX_USED
+        FROM performance_schema.events_statements_summary_by_digest
+        WHERE SCHEMA_NAME = DATABASE() AND DIGEST IS NOT NULL
+        ORDER BY SUM_TIMER_WAIT DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	digests := []Digest{}
+	for rows.Next() {
+		var d Digest
+		var latency, lockTime int64
+		err := rows.Scan(&d.Digest, &d.Text, &d.Count,
+			&latency, &lockTime,
+			&d.RowsExamined, &d.RowsSent, &d.RowsAffected,
+			&d.TmpTables, &d.TmpDiskTables,
+			&d.SortRows, &d.SortMergePasses, &d.SortScans,
+			&d.NoIndexUsed)
+		if err != nil {
+			return nil, err
+		}
+		d.Latency = time.Duration(latency)
+		d.LockTime = time.Duration(lockTime)
+		digests = append(digests, d)
+	}
+	return digests, rows.Err()
+}
+
+// Adds the statistics of other to d. Both should be for the same statement.
+func (d *Digest) Add(other Digest) {
+	d.Count += other.Count
+	d.Latency += other.Latency
+	d.LockTime += other.LockTime
+	d.RowsExamined += other.RowsExamined
+	d.RowsSent += other.RowsSent
+	d.RowsAffected += other.RowsAffected
+	d.TmpTables += other.TmpTables
+	d.TmpDiskTables += other.TmpDiskTables
+	d.SortRows += other.SortRows
+	d.SortMergePasses += other.SortMergePasses
+	d.SortScans += other.SortScans
+	d.NoIndexUsed += other.NoIndexUsed
+}
+
+// Adds up digests of the same statement and sorts them slowest first.
+func MergeDigests(digests ...[]Digest) []Digest {
+	merged := []Digest{}
+	index := map[string]int{}
+	for _, list := range digests {
+		for _, d := range list {
+			if i, ok := index[d.Digest]; ok {
+				merged[i].Add(d)
+				continue
+			}
+			index[d.Digest] = len(merged)
+			merged = append(merged, d)
+		}
+	}
+	slices.SortStableFunc(merged, func(a, b Digest) int {
+		return cmp.Compare(b.Latency, a.Latency)
+	})
+	return merged
+}
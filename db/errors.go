@@ -0,0 +1,61 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"syscall"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Classes of errors returned by ClassifyError.
+const (
+	ErrClassDeadlock       = "deadlock"
+	ErrClassTimeout        = "timeout"
+	ErrClassDuplicateKey   = "duplicate-key"
+	ErrClassConnectionLost = "connection-lost"
+	ErrClassOther          = "other"
+)
+
+// MySQL server error numbers.
+const (
+	erDupEntry        = 1062
+	erLockWaitTimeout = 1205
+	erLockDeadlock    = 1213
+)
+
+// Puts an error returned by the driver into one of the ErrClass classes, for
+// use with utils.Timer.SetErrorClassifier.
+func ClassifyError(err error) string {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case erLockDeadlock:
+			return ErrClassDeadlock
+		case erLockWaitTimeout:
+			return ErrClassTimeout
+		case erDupEntry:
+			return ErrClassDuplicateKey
+		}
+		return ErrClassOther
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return ErrClassTimeout
+	}
+	if errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, mysql.ErrInvalidConn) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, net.ErrClosed) {
+		return ErrClassConnectionLost
+	}
+	return ErrClassOther
+}
@@ -0,0 +1,35 @@
+package db_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/podocarp/mysql-test-test/db"
+)
+
+func TestClassifyError(t *testing.T) {
+	cases := []struct {
+		err   error
+		class string
+	}{
+		{&mysql.MySQLError{Number: 1213, Message: "Deadlock found"}, db.ErrClassDeadlock},
+		{fmt.Errorf("insert: %w", &mysql.MySQLError{Number: 1062}), db.ErrClassDuplicateKey},
+		{&mysql.MySQLError{Number: 1205}, db.ErrClassTimeout},
+		{context.DeadlineExceeded, db.ErrClassTimeout},
+		{driver.ErrBadConn, db.ErrClassConnectionLost},
+		{mysql.ErrInvalidConn, db.ErrClassConnectionLost},
+		{fmt.Errorf("read: %w", syscall.ECONNRESET), db.ErrClassConnectionLost},
+		{&mysql.MySQLError{Number: 1146}, db.ErrClassOther},
+		{errors.New("boom"), db.ErrClassOther},
+	}
+	for _, c := range cases {
+		if class := db.ClassifyError(c.err); class != c.class {
+			t.Errorf("%v: expected %s, obtained %s", c.err, c.class, class)
+		}
+	}
+}
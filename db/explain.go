@@ -0,0 +1,98 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// How a query accesses one table, from the EXPLAIN output.
+type TableAccess struct {
+	Table string
+	// ALL means a full table scan, const a primary key lookup, etc.
+	AccessType string
+	Key        string
+	Rows       int64
+}
+
+// The plan of a query as chosen by the optimizer.
+type Plan struct {
+	Query string
+	// Raw EXPLAIN FORMAT=JSON output.
+	JSON string
+	// EXPLAIN ANALYZE output, empty if the query is not a SELECT or the
+	// server does not support it.
+	Analyze  string
+	Tables   []TableAccess
+	FullScan bool
+	Filesort bool
+}
+
+// Captures the plan of a query. The args are only used to fill in the
+// placeholders. Since EXPLAIN ANALYZE runs the query, it is only used for
+// SELECTs.
+func Explain(pool *sql.DB, query string, args ...any) (Plan, error) {
+	var j string
+	err := pool.QueryRow(`EXPLAIN FORMAT=JSON `+query, args...).Scan(&j)
+	if err != nil {
+		return Plan{Query: query}, fmt.Errorf("explain %q: %w", query, err)
+	}
+	plan, err := ParsePlan(query, j)
+	if err != nil {
+		return plan, fmt.Errorf("explain %q: %w", query, err)
+	}
+
+	if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(query)), "SELECT") {
+		// Needs MySQL 8.0.18, so just go without on older servers.
+		pool.QueryRow(`EXPLAIN ANALYZE `+query, args...).Scan(&plan.Analyze)
+	}
+	return plan, nil
+}
+
+// Builds a plan from EXPLAIN FORMAT=JSON output.
+func ParsePlan(query, j string) (Plan, error) {
+	plan := Plan{Query: query, JSON: j}
+	var tree any
+	if err := json.Unmarshal([]byte(j), &tree); err != nil {
+		return plan, err
+	}
+	plan.walk(tree)
+	return plan, nil
+}
+
+// Collects the table accesses and flags from the json plan.
+func (p *Plan) walk(node any) {
+	switch node := node.(type) {
+	case map[string]any:
+		if b, ok := node["using_filesort"].(bool); ok && b {
+			p.Filesort = true
+		}
+		if table, ok := node["table_name"].(string); ok {
+			access := TableAccess{Table: table}
+			access.AccessType, _ = node["access_type"].(string)
+			access.Key, _ = node["key"].(string)
+			if rows, ok := node["rows_examined_per_scan"].(float64); ok {
+				access.Rows = int64(rows)
+			}
+			if access.AccessType == "ALL" {
+				p.FullScan = true
+			}
+			p.Tables = append(p.Tables, access)
+		}
+		// Sorted so tables are listed in the same order every time.
+		keys := make([]string, 0, len(node))
+		for key := range node {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			p.walk(node[key])
+		}
+	case []any:
+		for _, child := range node {
+			p.walk(child)
+		}
+	}
+}
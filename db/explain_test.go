@@ -0,0 +1,43 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/podocarp/mysql-test-test/db"
+)
+
+func TestParsePlan(t *testing.T) {
+	j := `{
+  "query_block": {
+    "select_id": 1,
+    "ordering_operation": {
+      "using_filesort": true,
+      "table": {
+        "table_name": "countries_json",
+        "access_type": "ALL",
+        "rows_examined_per_scan": 1000,
+        "filtered": "10.00"
+      }
+    }
+  }
+}`
+	plan, err := db.ParsePlan("SELECT id FROM countries_json ORDER BY countries", j)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.FullScan || !plan.Filesort {
+		t.Fatalf("Expected a full scan and a filesort, obtained %+v", plan)
+	}
+	if len(plan.Tables) != 1 || plan.Tables[0].Table != "countries_json" || plan.Tables[0].Rows != 1000 {
+		t.Fatalf("Unexpected tables %+v", plan.Tables)
+	}
+
+	j = `{"query_block": {"table": {"table_name": "countries_bitset", "access_type": "const", "key": "PRIMARY"}}}`
+	plan, err = db.ParsePlan("SELECT id FROM countries_bitset WHERE id=?", j)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.FullScan || plan.Filesort || plan.Tables[0].Key != "PRIMARY" {
+		t.Fatalf("Expected a primary key lookup, obtained %+v", plan)
+	}
+}
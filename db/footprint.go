@@ -0,0 +1,98 @@
+package db
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+)
+
+// How much space a table takes up.
+type Footprint struct {
+	Table string
+	// From information_schema.TABLES, e.g. InnoDB, Compressed and
+	// KEY_BLOCK_SIZE=8.
+	Engine        string
+	RowFormat     string
+	CreateOptions string
+	// Exact row count, from COUNT(*).
+	Rows         int64
+	DataLength   int64
+	IndexLength  int64
+	DataFree     int64
+	AvgRowLength int64
+	// Size of the table's .ibd file, or -1 if the table is not stored in its
+	// own tablespace.
+	FileSize int64
+	// Disk space the .ibd file takes up, less than FileSize if page
+	// compression punched holes into it. -1 if unknown.
+	AllocatedSize int64
+}
+
+// Bytes of data and indexes per row, or 0 for empty tables.
+func (f Footprint) BytesPerRow() float64 {
+	if f.Rows == 0 {
+		return 0
+	}
+	return float64(f.DataLength+f.IndexLength) / float64(f.Rows)
+}
+
+// Bytes of the .ibd file per row, or 0 if unknown.
+func (f Footprint) FileBytesPerRow() float64 {
+	if f.Rows == 0 || f.FileSize < 0 {
+		return 0
+	}
+	return float64(f.FileSize) / float64(f.Rows)
+}
+
+// Measures a table of the current database. Runs ANALYZE TABLE first so the
+// statistics in information_schema are up to date.
+func TableFootprint(pool *sql.DB, table string) (Footprint, error) {
+	f := Footprint{Table: table, FileSize: -1, AllocatedSize: -1}
+
+	// ANALYZE TABLE reports problems as rows rather than errors, which we
+	// don't care about here.
+	rows, err := pool.Query(`ANALYZE TABLE ` + table)
+	if err != nil {
+		return f, err
+	}
+	rows.Close()
+
+	err = pool.QueryRow(`SELECT COUNT(*) FROM ` + table).Scan(&f.Rows)
+	if err != nil {
+		return f, err
+	}
+	err = pool.QueryRow(`SELECT
+          COALESCE(ENGINE, ''), COALESCE(ROW_FORMAT, ''), COALESCE(CREATE_OPTIONS, ''),
+          COALESCE(DATA_LENGTH, 0), COALESCE(INDEX_LENGTH, 0),
+          COALESCE(DATA_FREE, 0), COALESCE(AVG_ROW_LENGTH, 0)
+        FROM information_schema.TABLES
+        WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?`, table).
+		Scan(&f.Engine, &f.RowFormat, &f.CreateOptions,
+			&f.DataLength, &f.IndexLength, &f.DataFree, &f.AvgRowLength)
+	if err != nil {
+		return f, err
+	}
+
+	// Works wherever the server runs, but only for InnoDB tables in their
+	// own tablespace.
+	err = pool.QueryRow(`SELECT FILE_SIZE, ALLOCATED_SIZE
+        FROM information_schema.INNODB_TABLESPACES
+        WHERE NAME = CONCAT(DATABASE(), '/', ?)`, table).
+		Scan(&f.FileSize, &f.AllocatedSize)
+	if err == nil {
+		return f, nil
+	} else if err != sql.ErrNoRows {
+		return f, err
+	}
+
+	var datadir, schema string
+	err = pool.QueryRow(`SELECT @@datadir, DATABASE()`).Scan(&datadir, &schema)
+	if err != nil {
+		return f, err
+	}
+	// Only works if the server runs on this machine, not e.g. in docker.
+	if info, err := os.Stat(filepath.Join(datadir, schema, table+".ibd")); err == nil {
+		f.FileSize = info.Size()
+	}
+	return f, nil
+}
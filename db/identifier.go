@@ -0,0 +1,13 @@
+package db
+
+import "regexp"
+
+// Names of variables, tables and columns are put into statements as is, so
+// only allow what real names look like.
+var identifier = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Reports whether a name can be put into a statement without quoting: letters,
+// digits and underscores, not starting with a digit.
+func ValidIdentifier(name string) bool {
+	return identifier.MatchString(name)
+}
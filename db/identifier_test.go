@@ -0,0 +1,20 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/podocarp/mysql-test-test/db"
+)
+
+func TestValidIdentifier(t *testing.T) {
+	for _, name := range []string{"countries_json", "innodb_flush_log_at_trx_commit", "_x1"} {
+		if !db.ValidIdentifier(name) {
+			t.Errorf("Expected %q to be valid", name)
+		}
+	}
+	for _, name := range []string{"", "1x", "a.b", "x; DROP TABLE y", "`x`"} {
+		if db.ValidIdentifier(name) {
+			t.Errorf("Expected %q to be invalid", name)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+// Package migrate applies versioned schema changes and records which ones a
+// database has in the schema_migrations table.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Keeps track of the applied migrations of every set.
+const trackingDDL = `CREATE TABLE IF NOT EXISTS schema_migrations (
+          schema_set VARCHAR(64) NOT NULL,
+          version INT NOT NULL,
+          name VARCHAR(255) NOT NULL,
+          applied_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+          PRIMARY KEY (schema_set, version)
+        ) ENGINE=InnoDB`
+
+// One schema change and how to undo it.
+type Migration struct {
+	// Positive, and increasing within a set. Never renumber a migration that
+	// was released, add a new one instead.
+	Version int
+	Name    string
+	// Statements that apply the change, run in order.
+	Up []string
+	// Statements that undo Up, run in order.
+	Down []string
+}
+
+// The migrations of one schema, e.g. the table of a strategy.
+type Set struct {
+	// Key of the set in schema_migrations.
+	Name       string
+	Migrations []Migration
+}
+
+// Whether a migration has been applied, see Status.
+type State struct {
+	Migration
+	Applied bool
+	// When it was applied, zero if it was not.
+	AppliedAt time.Time
+}
+
+// Checks that versions are positive and increasing.
+func (s Set) Validate() error {
+	last := 0
+	for _, m := range s.Migrations {
+		if m.Version <= last {
+			return fmt.Errorf("%s: migration %d %s: versions must be positive and increasing", s.Name, m.Version, m.Name)
+		}
+		last = m.Version
+	}
+	return nil
+}
+
+// Version of the last migration, 0 if there are none.
+func (s Set) Latest() int {
+	if len(s.Migrations) == 0 {
+		return 0
+	}
+	return s.Migrations[len(s.Migrations)-1].Version
+}
+
+// Returns the migrations that take the schema from one version to another, in
+// the order they run: ascending to go up, descending to go down.
+func (s Set) Plan(from, to int) ([]Migration, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	if to != 0 && !slices.ContainsFunc(s.Migrations, func(m Migration) bool { return m.Version == to }) {
+		return nil, fmt.Errorf("%s: no migration %d", s.Name, to)
+	}
+	plan := []Migration{}
+	for _, m := range s.Migrations {
+		if from < to && m.Version > from && m.Version <= to {
+			plan = append(plan, m)
+		} else if from > to && m.Version <= from && m.Version > to {
+			plan = append(plan, m)
+		}
+	}
+	if from > to {
+		slices.Reverse(plan)
+	}
+	return plan, nil
+}
+
+// Like Plan, but fails if to is below from rather than going down.
+func (s Set) PlanUp(from, to int) ([]Migration, error) {
+	if to < from {
+		return nil, fmt.Errorf("%s: at version %d, up cannot go back to %d", s.Name, from, to)
+	}
+	return s.Plan(from, to)
+}
+
+// Like Plan, but fails if to is above from rather than going up.
+func (s Set) PlanDown(from, to int) ([]Migration, error) {
+	if to > from {
+		return nil, fmt.Errorf("%s: at version %d, down cannot go forward to %d", s.Name, from, to)
+	}
+	return s.Plan(from, to)
+}
+
+// Returns the version of the set the database is at, 0 if nothing was
+// applied. Fails if the database has a version the set does not know, e.g.
+// one applied by a newer build.
+func Version(ctx context.Context, pool *sql.DB, s Set) (int, error) {
+	applied, err := appliedAt(ctx, pool, s)
+	if err != nil {
+		return 0, err
+	}
+	version := 0
+	for v := range applied {
+		if !slices.ContainsFunc(s.Migrations, func(m Migration) bool { return m.Version == v }) {
+			return 0, fmt.Errorf("%s: database has unknown migration %d", s.Name, v)
+		}
+		version = max(version, v)
+	}
+	return version, nil
+}
+
+// Applies every migration that has not been yet. Returns the migrations that
+// were applied.
+func Up(ctx context.Context, pool *sql.DB, s Set) ([]Migration, error) {
+	return UpTo(ctx, pool, s, s.Latest())
+}
+
+// Undoes every applied migration.
+func Down(ctx context.Context, pool *sql.DB, s Set) ([]Migration, error) {
+	return DownTo(ctx, pool, s, 0)
+}
+
+// Undoes every applied migration and applies them all again, which leaves an
+// empty schema at the latest version.
+func Reset(ctx context.Context, pool *sql.DB, s Set) error {
+	if _, err := Down(ctx, pool, s); err != nil {
+		return err
+	}
+	_, err := Up(ctx, pool, s)
+	return err
+}
+
+// Migrates up or down to the given version, 0 to undo everything. Each
+// migration is recorded once all its statements ran. MySQL commits DDL
+// implicitly, so if a statement fails the schema is left half migrated and
+// has to be fixed by hand.
+func To(ctx context.Context, pool *sql.DB, s Set, version int) ([]Migration, error) {
+	return migrate(ctx, pool, s, version, s.Plan)
+}
+
+// Like To, but fails if the database is already past the version.
+func UpTo(ctx context.Context, pool *sql.DB, s Set, version int) ([]Migration, error) {
+	return migrate(ctx, pool, s, version, s.PlanUp)
+}
+
+// Like To, but fails if the database is not yet at the version.
+func DownTo(ctx context.Context, pool *sql.DB, s Set, version int) ([]Migration, error) {
+	return migrate(ctx, pool, s, version, s.PlanDown)
+}
+
+func migrate(ctx context.Context, pool *sql.DB, s Set, version int, planner func(from, to int) ([]Migration, error)) ([]Migration, error) {
+	current, err := Version(ctx, pool, s)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := planner(current, version)
+	if err != nil {
+		return nil, err
+	}
+	for i, m := range plan {
+		up := current < version
+		statements := m.Down
+		if up {
+			statements = m.Up
+		}
+		for _, statement := range statements {
+			if _, err := pool.ExecContext(ctx, statement); err != nil {
+				return plan[:i], fmt.Errorf("%s: migration %d %s: %w", s.Name, m.Version, m.Name, err)
+			}
+		}
+		if up {
+			_, err = pool.ExecContext(ctx, `INSERT INTO schema_migrations (schema_set, version, name) VALUES (?, ?, ?)`,
+				s.Name, m.Version, m.Name)
+		} else {
+			_, err = pool.ExecContext(ctx, `DELETE FROM schema_migrations WHERE schema_set = ? AND version = ?`,
+				s.Name, m.Version)
+		}
+		if err != nil {
+			return plan[:i], fmt.Errorf("%s: recording migration %d: %w", s.Name, m.Version, err)
+		}
+	}
+	return plan, nil
+}
+
+// Lists every migration of the set and whether it was applied.
+func Status(ctx context.Context, pool *sql.DB, s Set) ([]State, error) {
+	if _, err := Version(ctx, pool, s); err != nil {
+		return nil, err
+	}
+	applied, err := appliedAt(ctx, pool, s)
+	if err != nil {
+		return nil, err
+	}
+	states := make([]State, len(s.Migrations))
+	for i, m := range s.Migrations {
+		at, ok := applied[m.Version]
+		states[i] = State{Migration: m, Applied: ok, AppliedAt: at}
+	}
+	return states, nil
+}
+
+// Reads the applied versions of a set and when they were applied. Creates the
+// tracking table if needed.
+func appliedAt(ctx context.Context, pool *sql.DB, s Set) (map[int]time.Time, error) {
+	if _, err := pool.ExecContext(ctx, trackingDDL); err != nil {
+		return nil, err
+	}
+	rows, err := pool.QueryContext(ctx, `SELECT version, CAST(applied_at AS CHAR) FROM schema_migrations WHERE schema_set = ?`, s.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var at string
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		// The driver only parses times with parseTime=true, which the
+		// pool is not opened with.
+		applied[version], _ = time.Parse("2006-01-02 15:04:05.999999", at)
+	}
+	return applied, rows.Err()
+}
@@ -0,0 +1,91 @@
+package migrate_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/podocarp/mysql-test-test/db/migrate"
+)
+
+var set = migrate.Set{
+	Name: "test",
+	Migrations: []migrate.Migration{
+		{Version: 1, Name: "create"},
+		{Version: 2, Name: "add index"},
+		{Version: 5, Name: "add column"},
+	},
+}
+
+func versions(plan []migrate.Migration) []int {
+	v := []int{}
+	for _, m := range plan {
+		v = append(v, m.Version)
+	}
+	return v
+}
+
+func TestPlan(t *testing.T) {
+	cases := []struct {
+		from, to int
+		want     []int
+	}{
+		{0, 5, []int{1, 2, 5}},
+		{1, 5, []int{2, 5}},
+		{0, 2, []int{1, 2}},
+		{5, 0, []int{5, 2, 1}},
+		{5, 1, []int{5, 2}},
+		{2, 2, []int{}},
+	}
+	for _, c := range cases {
+		plan, err := set.Plan(c.from, c.to)
+		if err != nil {
+			t.Fatalf("%d to %d: %v", c.from, c.to, err)
+		}
+		if got := versions(plan); !slices.Equal(got, c.want) {
+			t.Errorf("%d to %d: expected %v, obtained %v", c.from, c.to, c.want, got)
+		}
+	}
+
+	if _, err := set.Plan(0, 3); err == nil {
+		t.Error("Expected migrating to a missing version to fail")
+	}
+	if set.Latest() != 5 {
+		t.Errorf("Expected latest version 5, obtained %d", set.Latest())
+	}
+}
+
+func TestPlanDirection(t *testing.T) {
+	if _, err := set.PlanUp(5, 2); err == nil {
+		t.Error("Expected up to a lower version to fail")
+	}
+	if _, err := set.PlanDown(2, 5); err == nil {
+		t.Error("Expected down to a higher version to fail")
+	}
+	if plan, err := set.PlanUp(1, 5); err != nil || !slices.Equal(versions(plan), []int{2, 5}) {
+		t.Errorf("Expected up from 1 to 5 to apply [2 5], obtained %v, %v", versions(plan), err)
+	}
+	if plan, err := set.PlanDown(5, 1); err != nil || !slices.Equal(versions(plan), []int{5, 2}) {
+		t.Errorf("Expected down from 5 to 1 to undo [5 2], obtained %v, %v", versions(plan), err)
+	}
+	for _, plan := range []func(from, to int) ([]migrate.Migration, error){set.PlanUp, set.PlanDown} {
+		if steps, err := plan(2, 2); err != nil || len(steps) != 0 {
+			t.Errorf("Expected staying at a version to do nothing, obtained %v, %v", steps, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := set.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	invalid := []migrate.Set{
+		{Name: "zero", Migrations: []migrate.Migration{{Version: 0}}},
+		{Name: "unordered", Migrations: []migrate.Migration{{Version: 2}, {Version: 1}}},
+		{Name: "duplicate", Migrations: []migrate.Migration{{Version: 1}, {Version: 1}}},
+	}
+	for _, s := range invalid {
+		if err := s.Validate(); err == nil {
+			t.Errorf("Expected %s to be invalid", s.Name)
+		}
+	}
+}
@@ -7,21 +7,50 @@ import (
 	_ "github.com/go-sql-driver/mysql"
 )
 
-func Connect() *sql.DB {
+// Opens a connection pool to the test database started by start.sh and checks
+// that the server is reachable.
+func Open() (*sql.DB, error) {
+	return OpenAt("")
+}
+
+// Like Open, but connects to the given host:port instead of localhost:3306,
+// e.g. a proxy in front of the server.
+func OpenAt(addr string) (*sql.DB, error) {
+	if addr != "" {
+		addr = "tcp(" + addr + ")"
+	}
 	dsn := fmt.Sprintf("%s:%s@%s/%s?%s",
 		"root", "asd", // user, password
-		"",     // address, empty for localhost:3306
+		addr,   // address, empty for localhost:3306
 		"test", // db
 		"",     // options
 	)
 	db, err := sql.Open("mysql", dsn)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	err = db.Ping()
 	if err != nil {
-		panic(err)
+		db.Close()
+		return nil, err
 	}
 
+	return db, nil
+}
+
+// Like Open, but panics if the database cannot be reached.
+func Connect() *sql.DB {
+	return ConnectTo("")
+}
+
+// Like OpenAt, but panics if the database cannot be reached.
+func ConnectTo(addr string) *sql.DB {
+	db, err := OpenAt(addr)
+	if err != nil {
+		panic(err)
+	}
 	return db
 }
+
+// Address of the server started by start.sh.
+const ServerAddr = "127.0.0.1:3306"
@@ -0,0 +1,140 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// ER_INCORRECT_GLOBAL_LOCAL_VAR, returned when setting a read only variable.
+const erIncorrectGlobalLocalVar = 1238
+
+// A named set of server variables that results depend on, e.g. how often the
+// redo log is flushed.
+type ServerProfile struct {
+	Name      string
+	Variables map[string]string
+}
+
+var serverProfiles = []ServerProfile{
+	{
+		// What a production server would run with: every commit is
+		// flushed to disk and binlogged.
+		Name: "durable",
+		Variables: map[string]string{
+			"innodb_flush_log_at_trx_commit": "1",
+			"sync_binlog":                    "1",
+			"log_bin":                        "ON",
+		},
+	},
+	{
+		// What conf/my.cnf sets up: the redo log is flushed about once a
+		// second and there is no binlog.
+		Name: "relaxed",
+		Variables: map[string]string{
+			"innodb_flush_log_at_trx_commit": "2",
+			"sync_binlog":                    "0",
+			"log_bin":                        "OFF",
+		},
+	},
+}
+
+// Returns all known server profiles.
+func ServerProfiles() []ServerProfile {
+	return serverProfiles
+}
+
+// Looks up a server profile by its name.
+func ServerProfileByName(name string) (ServerProfile, bool) {
+	for _, p := range serverProfiles {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return ServerProfile{}, false
+}
+
+// Returns the names of the variables set by any of the profiles, sorted.
+func ProfileVariables(profiles ...ServerProfile) []string {
+	names := []string{}
+	for _, p := range profiles {
+		for name := range p.Variables {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// What ApplyProfile did.
+type Applied struct {
+	// Values from before the profile was applied, of the variables it
+	// changed. Pass them to SetGlobalVariables to undo.
+	Previous map[string]string
+	// Static variables that differ from the profile, with their current
+	// value. They only change when the server restarts with a different
+	// config, or after persisting them.
+	NeedsRestart map[string]string
+}
+
+// Sets the variables of a profile that differ from the server's. Dynamic
+// variables are set with SET GLOBAL, or SET PERSIST if persist is set so they
+// survive a restart. Static variables are listed in Applied.NeedsRestart, and
+// with persist they are written with SET PERSIST_ONLY so the next restart
+// picks them up.
+func ApplyProfile(pool *sql.DB, p ServerProfile, persist bool) (Applied, error) {
+	applied := Applied{Previous: map[string]string{}, NeedsRestart: map[string]string{}}
+	names := ProfileVariables(p)
+	current, err := GlobalVariables(pool, names...)
+	if err != nil {
+		return applied, err
+	}
+
+	scope := "GLOBAL"
+	if persist {
+		scope = "PERSIST"
+	}
+	for _, name := range names {
+		value := p.Variables[name]
+		if SameValue(current[name], value) {
+			continue
+		}
+		err := setVariable(pool, scope, name, value)
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == erIncorrectGlobalLocalVar {
+			applied.NeedsRestart[name] = current[name]
+			if persist {
+				err = setVariable(pool, "PERSIST_ONLY", name, value)
+			} else {
+				err = nil
+			}
+		} else if err == nil {
+			applied.Previous[name] = current[name]
+		}
+		if err != nil {
+			return applied, fmt.Errorf("%s %s: %w", p.Name, name, err)
+		}
+	}
+	return applied, nil
+}
+
+// Reports whether two variable values mean the same thing. The server reads
+// boolean variables back as 0 and 1.
+func SameValue(a, b string) bool {
+	normalize := func(v string) string {
+		switch strings.ToUpper(v) {
+		case "ON", "TRUE":
+			return "1"
+		case "OFF", "FALSE":
+			return "0"
+		}
+		return v
+	}
+	return normalize(a) == normalize(b)
+}
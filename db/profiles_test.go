@@ -0,0 +1,40 @@
+package db_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/podocarp/mysql-test-test/db"
+)
+
+func TestSameValue(t *testing.T) {
+	cases := []struct {
+		a, b string
+		same bool
+	}{
+		{"1", "ON", true},
+		{"0", "off", true},
+		{"2", "2", true},
+		{"1", "OFF", false},
+		{"ROW", "row", false},
+	}
+	for _, c := range cases {
+		if db.SameValue(c.a, c.b) != c.same {
+			t.Errorf("Expected SameValue(%q, %q) to be %v", c.a, c.b, c.same)
+		}
+	}
+}
+
+func TestServerProfiles(t *testing.T) {
+	durable, ok := db.ServerProfileByName("durable")
+	if !ok {
+		t.Fatalf("Expected a durable profile")
+	}
+	relaxed, _ := db.ServerProfileByName("relaxed")
+	// Both profiles must set the same variables, or switching from one to
+	// the other would leave some behind.
+	if !slices.Equal(db.ProfileVariables(durable), db.ProfileVariables(relaxed)) {
+		t.Fatalf("Profiles set different variables: %v and %v",
+			db.ProfileVariables(durable), db.ProfileVariables(relaxed))
+	}
+}
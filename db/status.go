@@ -0,0 +1,133 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/podocarp/mysql-test-test/utils"
+)
+
+// Prefixes of the global status variables recorded by StatusSampler.
+var statusPrefixes = []string{
+	"Innodb_rows_",
+	"Innodb_buffer_pool_read",
+	"Innodb_data_",
+	"Handler_",
+	"Bytes_",
+	"Com_",
+}
+
+// Records how much the server's global status counters changed during each
+// iteration, e.g. Innodb_rows_inserted or Bytes_sent. Every variable is
+// reported as <name>/op on every iteration, 0 if it did not change;
+// StatusUnits leaves out the dozens of Com_ counters that never do.
+//
+// The counters are global, so anything else running on the server is included,
+// and so is the SHOW GLOBAL STATUS issued at the start of the iteration.
+type StatusSampler struct {
+	pool   *sql.DB
+	before map[string]float64
+}
+
+func NewStatusSampler(pool *sql.DB) *StatusSampler {
+	return &StatusSampler{pool: pool}
+}
+
+func (s *StatusSampler) Start() {
+	var err error
+	s.before, err = GlobalStatus(s.pool)
+	if err != nil {
+		fmt.Println("Could not read server status:", err)
+	}
+}
+
+func (s *StatusSampler) Stop() []utils.Metric {
+	if s.before == nil {
+		return nil
+	}
+	after, err := GlobalStatus(s.pool)
+	if err != nil {
+		fmt.Println("Could not read server status:", err)
+		return nil
+	}
+
+	names := make([]string, 0, len(after))
+	for name := range after {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	result := []utils.Metric{}
+	for _, name := range names {
+		result = append(result, utils.Metric{Value: after[name] - s.before[name], Unit: name + "/op"})
+	}
+	return result
+}
+
+// Reads the numeric global status variables matching statusPrefixes.
+func GlobalStatus(pool *sql.DB) (map[string]float64, error) {
+	conditions := make([]string, len(statusPrefixes))
+	for i, prefix := range statusPrefixes {
+		conditions[i] = "Variable_name LIKE '" + prefix + "%'"
+	}
+	rows, err := pool.Query(`SHOW GLOBAL STATUS WHERE ` + strings.Join(conditions, " OR "))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	status := map[string]float64{}
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, err
+		}
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			status[name] = n
+		}
+	}
+	return status, rows.Err()
+}
+
+// Returns the units of all metrics of the timers that were recorded by a
+// StatusSampler, sorted by name. Counters that never changed are left out.
+func StatusUnits(timers ...*utils.Timer) []string {
+	units := []string{}
+	for _, t := range timers {
+		for _, unit := range t.Units() {
+			if !t.NonZero(unit) {
+				continue
+			}
+			for _, prefix := range statusPrefixes {
+				if strings.HasPrefix(unit, prefix) && !slices.Contains(units, unit) {
+					units = append(units, unit)
+					break
+				}
+			}
+		}
+	}
+	slices.Sort(units)
+	return units
+}
+
+// Returns the number of threads executing a statement, a rough measure of how
+// busy the server is.
+func ThreadsRunning(pool *sql.DB) (int64, error) {
+	var name string
+	var n int64
+	err := pool.QueryRow(`SHOW GLOBAL STATUS LIKE 'Threads_running'`).Scan(&name, &n)
+	return n, err
+}
+
+// Returns the InnoDB history list length: undo log entries of committed
+// transactions that purge has not caught up with yet. Like replication lag, it
+// grows when writes come in faster than the server can clean up after them.
+func HistoryLength(pool *sql.DB) (int64, error) {
+	var n int64
+	err := pool.QueryRow(`SELECT COUNT FROM information_schema.INNODB_METRICS
+        WHERE NAME = 'trx_rseg_history_len'`).Scan(&n)
+	return n, err
+}
@@ -0,0 +1,60 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Reads the global value of each variable.
+func GlobalVariables(pool *sql.DB, names ...string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, name := range names {
+		if !ValidIdentifier(name) {
+			return nil, fmt.Errorf("invalid variable name %q", name)
+		}
+		var value sql.NullString
+		if err := pool.QueryRow(`SELECT @@GLOBAL.` + name).Scan(&value); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		values[name] = value.String
+	}
+	return values, nil
+}
+
+// Sets global variables with SET GLOBAL and returns their previous values, so
+// they can be restored by calling this again. Needs the SYSTEM_VARIABLES_ADMIN
+// privilege, which root has.
+func SetGlobalVariables(pool *sql.DB, vars map[string]string) (map[string]string, error) {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	previous, err := GlobalVariables(pool, names...)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if err := setVariable(pool, "GLOBAL", name, vars[name]); err != nil {
+			return previous, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return previous, nil
+}
+
+// Runs SET with the given scope, e.g. GLOBAL or PERSIST. The name must have
+// been checked already.
+func setVariable(pool *sql.DB, scope, name, value string) error {
+	_, err := pool.Exec(`SET ` + scope + ` ` + name + ` = ` + sqlValue(value))
+	return err
+}
+
+// Formats a variable value for SET. Numeric variables reject strings, so
+// numbers are left unquoted.
+func sqlValue(v string) string {
+	if _, err := strconv.ParseFloat(v, 64); err == nil {
+		return v
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v) + "'"
+}
@@ -3,9 +3,9 @@ module github.com/podocarp/mysql-test-test
 go 1.23.3
 
 require (
-	github.com/go-echarts/go-echarts v1.0.0
 	github.com/go-echarts/go-echarts/v2 v2.4.5
 	github.com/go-sql-driver/mysql v1.8.1
+	github.com/google/pprof v0.0.0-20241210010833-40e02aabc2ad
 )
 
 require filippo.io/edwards25519 v1.1.0 // indirect
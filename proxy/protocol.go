@@ -0,0 +1,90 @@
+package proxy
+
+import "fmt"
+
+// The start of a MySQL packet.
+type packet struct {
+	seq byte
+	// The first byte of the payload, which is the command for packets sent
+	// by the client.
+	command    byte
+	hasCommand bool
+}
+
+// Splits a stream of bytes into MySQL packets. Every packet starts with a 3
+// byte little endian payload length and a 1 byte sequence id.
+type packetParser struct {
+	header    [4]byte
+	headerLen int
+	// Payload bytes of the current packet still to be skipped.
+	remaining int
+	current   packet
+	// Whether the first payload byte of the current packet was read yet.
+	needCommand bool
+}
+
+// Consumes the bytes and returns the packets whose first payload byte, or
+// header if they are empty, was in them.
+func (p *packetParser) feed(b []byte) []packet {
+	packets := []packet{}
+	for len(b) > 0 {
+		if p.remaining > 0 {
+			if p.needCommand {
+				p.current.command = b[0]
+				p.current.hasCommand = true
+				p.needCommand = false
+				packets = append(packets, p.current)
+			}
+			skip := min(p.remaining, len(b))
+			p.remaining -= skip
+			b = b[skip:]
+			continue
+		}
+
+		n := copy(p.header[p.headerLen:], b)
+		p.headerLen += n
+		b = b[n:]
+		if p.headerLen < len(p.header) {
+			break
+		}
+		p.headerLen = 0
+		p.remaining = int(p.header[0]) | int(p.header[1])<<8 | int(p.header[2])<<16
+		p.current = packet{seq: p.header[3]}
+		p.needCommand = p.remaining > 0
+		if !p.needCommand {
+			packets = append(packets, p.current)
+		}
+	}
+	return packets
+}
+
+var commandNames = map[byte]string{
+	0x01: "COM_QUIT",
+	0x02: "COM_INIT_DB",
+	0x03: "COM_QUERY",
+	0x04: "COM_FIELD_LIST",
+	0x0e: "COM_PING",
+	0x11: "COM_CHANGE_USER",
+	0x16: "COM_STMT_PREPARE",
+	0x17: "COM_STMT_EXECUTE",
+	0x18: "COM_STMT_SEND_LONG_DATA",
+	0x19: "COM_STMT_CLOSE",
+	0x1a: "COM_STMT_RESET",
+	0x1b: "COM_SET_OPTION",
+	0x1c: "COM_STMT_FETCH",
+	0x1f: "COM_RESET_CONNECTION",
+}
+
+// Commands the server does not reply to.
+var noResponse = map[byte]bool{
+	0x01: true, // COM_QUIT
+	0x18: true, // COM_STMT_SEND_LONG_DATA
+	0x19: true, // COM_STMT_CLOSE
+}
+
+func commandName(command byte) string {
+	if name, ok := commandNames[command]; ok {
+		return name
+	}
+	return fmt.Sprintf("COM_0x%02x", command)
+}
@@ -0,0 +1,310 @@
+package proxy
+
+import (
+	"errors"
+	"io"
+	"maps"
+	"math/rand/v2"
+	"net"
+	"sync"
+	"time"
+)
+
+// Traffic in one direction.
+type Counters struct {
+	Bytes int64
+	// MySQL protocol packets.
+	Packets int64
+}
+
+// Everything a Proxy has seen since it started or was last reset.
+type Stats struct {
+	ClientToServer Counters
+	ServerToClient Counters
+	// Commands that the server answers, so each one is a round trip.
+	RoundTrips int64
+	// Number of commands sent by the client, by name, e.g. COM_QUERY.
+	Commands    map[string]int64
+	Connections int64
+	// Connections reset on purpose, see Faults.ResetRate.
+	Resets int64
+}
+
+// Degrades the traffic through the proxy to simulate a server across a
+// network. The zero value forwards everything as fast as possible.
+type Faults struct {
+	// Added to data in each direction, so a round trip gets twice this.
+	Latency time.Duration
+	// Random extra latency, uniform between 0 and Jitter. Data is never
+	// reordered, so it can also delay data behind it.
+	Jitter time.Duration
+	// Bytes per second in each direction of each connection, 0 for no
+	// limit.
+	Bandwidth int64
+	// Probability that a connection is reset whenever the client sends
+	// something, between 0 and 1.
+	ResetRate float64
+}
+
+// A transparent TCP proxy between MySQL clients and a server, which counts the
+// traffic going through it. It parses just enough of the MySQL protocol to
+// count packets and commands, so it does not work with TLS or compression.
+type Proxy struct {
+	listener net.Listener
+	target   string
+
+	mu     sync.Mutex
+	stats  Stats
+	faults Faults
+	conns  map[net.Conn]struct{}
+	wg     sync.WaitGroup
+}
+
+// Listens on listen, e.g. 127.0.0.1:0 for any free port, and forwards every
+// connection to target.
+func Start(listen, target string) (*Proxy, error) {
+	listener, err := net.Listen("tcp", listen)
+	if err != nil {
+		return nil, err
+	}
+	p := &Proxy{
+		listener: listener,
+		target:   target,
+		stats:    Stats{Commands: map[string]int64{}},
+		conns:    map[net.Conn]struct{}{},
+	}
+	p.wg.Add(1)
+	go p.serve()
+	return p, nil
+}
+
+// The address clients should connect to.
+func (p *Proxy) Addr() string {
+	return p.listener.Addr().String()
+}
+
+// Returns a copy of the current counters.
+func (p *Proxy) Stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := p.stats
+	stats.Commands = maps.Clone(p.stats.Commands)
+	return stats
+}
+
+// Changes the network conditions, including for open connections.
+func (p *Proxy) SetFaults(faults Faults) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.faults = faults
+}
+
+func (p *Proxy) Faults() Faults {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.faults
+}
+
+// Zeroes all counters.
+func (p *Proxy) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stats = Stats{Commands: map[string]int64{}}
+}
+
+// Stops listening and closes all open connections.
+func (p *Proxy) Close() error {
+	err := p.listener.Close()
+	p.mu.Lock()
+	for conn := range p.conns {
+		conn.Close()
+	}
+	p.mu.Unlock()
+	p.wg.Wait()
+	return err
+}
+
+func (p *Proxy) serve() {
+	defer p.wg.Done()
+	for {
+		client, err := p.listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+		if err != nil {
+			continue
+		}
+		p.wg.Add(1)
+		go p.handle(client)
+	}
+}
+
+func (p *Proxy) track(conn net.Conn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.conns[conn] = struct{}{}
+}
+
+func (p *Proxy) untrack(conn net.Conn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.conns, conn)
+	conn.Close()
+}
+
+func (p *Proxy) handle(client net.Conn) {
+	defer p.wg.Done()
+	p.track(client)
+	defer p.untrack(client)
+
+	server, err := net.Dial("tcp", p.target)
+	if err != nil {
+		return
+	}
+	p.track(server)
+	defer p.untrack(server)
+
+	p.mu.Lock()
+	p.stats.Connections++
+	p.mu.Unlock()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		p.pipe(server, client, true)
+		done <- struct{}{}
+	}()
+	go func() {
+		p.pipe(client, server, false)
+		done <- struct{}{}
+	}()
+	<-done
+	<-done
+}
+
+// Data read from one side, to be written to the other at a later time.
+type chunk struct {
+	data []byte
+	due  time.Time
+	// What data was read into, put back into buffers once written.
+	buf *[]byte
+}
+
+// Read buffers. With latency a connection can have many chunks in flight, so
+// buffers are shared and reused once written rather than allocated per read.
+var buffers = sync.Pool{New: func() any {
+	b := make([]byte, 32*1024)
+	return &b
+}}
+
+// Forwards everything from src to dst, applying the current faults.
+func (p *Proxy) pipe(dst, src net.Conn, fromClient bool) {
+	// Unblock the other direction once done.
+	defer dst.Close()
+	defer src.Close()
+
+	// Reading and writing happen separately so that latency delays data
+	// without slowing down how fast it is accepted, like a real network.
+	chunks := make(chan chunk, 64)
+	go func() {
+		defer close(chunks)
+		r := &countingReader{r: src, proxy: p, fromClient: fromClient}
+		var last time.Time
+		for {
+			buf := buffers.Get().(*[]byte)
+			n, err := r.Read(*buf)
+			if n > 0 {
+				faults := p.Faults()
+				if fromClient && faults.ResetRate > 0 && rand.Float64() < faults.ResetRate {
+					buffers.Put(buf)
+					p.reset(src)
+					return
+				}
+				due := time.Now().Add(faults.Latency)
+				if faults.Jitter > 0 {
+					due = due.Add(rand.N(faults.Jitter))
+				}
+				if due.Before(last) {
+					due = last
+				}
+				last = due
+				chunks <- chunk{(*buf)[:n], due, buf}
+			} else {
+				buffers.Put(buf)
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	for c := range chunks {
+		time.Sleep(time.Until(c.due))
+		_, err := dst.Write(c.data)
+		buffers.Put(c.buf)
+		if err != nil {
+			src.Close()
+			// Let the reader finish.
+			for c := range chunks {
+				buffers.Put(c.buf)
+			}
+			return
+		}
+		if bandwidth := p.Faults().Bandwidth; bandwidth > 0 {
+			time.Sleep(time.Duration(len(c.data)) * time.Second / time.Duration(bandwidth))
+		}
+	}
+}
+
+// Closes the client connection with a TCP reset, which the client sees as the
+// connection being lost.
+func (p *Proxy) reset(client net.Conn) {
+	p.mu.Lock()
+	p.stats.Resets++
+	p.mu.Unlock()
+
+	if tcp, ok := client.(*net.TCPConn); ok {
+		tcp.SetLinger(0)
+	}
+	client.Close()
+}
+
+// Counts bytes and packets as they are read from one side of a connection.
+type countingReader struct {
+	r          io.Reader
+	proxy      *Proxy
+	fromClient bool
+	parser     packetParser
+}
+
+func (c *countingReader) Read(b []byte) (int, error) {
+	n, err := c.r.Read(b)
+	if n > 0 {
+		packets := c.parser.feed(b[:n])
+
+		p := c.proxy
+		p.mu.Lock()
+		counters := &p.stats.ServerToClient
+		if c.fromClient {
+			counters = &p.stats.ClientToServer
+		}
+		counters.Bytes += int64(n)
+		counters.Packets += int64(len(packets))
+		if c.fromClient {
+			for _, packet := range packets {
+				// Every command starts a new sequence, anything else is
+				// part of the handshake or a continuation of a large
+				// packet.
+				if packet.seq != 0 || !packet.hasCommand {
+					continue
+				}
+				name := commandName(packet.command)
+				p.stats.Commands[name]++
+				if !noResponse[packet.command] {
+					p.stats.RoundTrips++
+				}
+			}
+		}
+		p.mu.Unlock()
+	}
+	return n, err
+}
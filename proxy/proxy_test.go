@@ -0,0 +1,185 @@
+package proxy_test
+
+import (
+	"io"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/podocarp/mysql-test-test/proxy"
+	"github.com/podocarp/mysql-test-test/utils"
+)
+
+func mysqlPacket(seq byte, payload ...byte) []byte {
+	n := len(payload)
+	return append([]byte{byte(n), byte(n >> 8), byte(n >> 16), seq}, payload...)
+}
+
+func TestProxyCounts(t *testing.T) {
+	server, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	greeting := mysqlPacket(0, 10, 'h', 'i')
+	handshake := mysqlPacket(1, 1, 2, 3, 4, 5)
+	query := mysqlPacket(0, 0x03, 'S', 'E', 'L', 'E', 'C', 'T', ' ', '1')
+	ok := mysqlPacket(1, 0x00, 0, 0)
+	closeStmt := mysqlPacket(0, 0x19, 1, 0, 0, 0)
+
+	received := make(chan []byte)
+	go func() {
+		conn, err := server.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.Write(greeting)
+		b := make([]byte, len(handshake)+len(query))
+		io.ReadFull(conn, b)
+		conn.Write(ok)
+		b = make([]byte, len(closeStmt))
+		io.ReadFull(conn, b)
+		received <- b
+	}()
+
+	p, err := proxy.Start("127.0.0.1:0", server.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	client, err := net.Dial("tcp", p.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	io.ReadFull(client, make([]byte, len(greeting)))
+	// Split a packet across writes to check the parser keeps its state.
+	client.Write(handshake[:2])
+	client.Write(append(handshake[2:], query...))
+	io.ReadFull(client, make([]byte, len(ok)))
+	client.Write(closeStmt)
+	<-received
+
+	stats := p.Stats()
+	if stats.Connections != 1 {
+		t.Fatalf("Expected 1 connection, obtained %d", stats.Connections)
+	}
+	sent := int64(len(handshake) + len(query) + len(closeStmt))
+	if stats.ClientToServer.Bytes != sent || stats.ClientToServer.Packets != 3 {
+		t.Fatalf("Unexpected client traffic %+v", stats.ClientToServer)
+	}
+	if stats.ServerToClient.Bytes != int64(len(greeting)+len(ok)) || stats.ServerToClient.Packets != 2 {
+		t.Fatalf("Unexpected server traffic %+v", stats.ServerToClient)
+	}
+	if stats.Commands["COM_QUERY"] != 1 || stats.Commands["COM_STMT_CLOSE"] != 1 || len(stats.Commands) != 2 {
+		t.Fatalf("Unexpected commands %v", stats.Commands)
+	}
+	// COM_STMT_CLOSE has no response.
+	if stats.RoundTrips != 1 {
+		t.Fatalf("Expected 1 round trip, obtained %d", stats.RoundTrips)
+	}
+
+	p.Reset()
+	if stats := p.Stats(); stats.ClientToServer.Bytes != 0 || len(stats.Commands) != 0 {
+		t.Fatalf("Expected reset stats, obtained %+v", stats)
+	}
+}
+
+// Starts a server that echoes everything back and a proxy in front of it.
+func startEcho(t *testing.T) *proxy.Proxy {
+	t.Helper()
+	server, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { server.Close() })
+	go func() {
+		for {
+			conn, err := server.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				io.Copy(conn, conn)
+				conn.Close()
+			}()
+		}
+	}()
+
+	p, err := proxy.Start("127.0.0.1:0", server.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { p.Close() })
+	return p
+}
+
+func TestProxyLatency(t *testing.T) {
+	p := startEcho(t)
+	p.SetFaults(proxy.Faults{Latency: 20 * time.Millisecond, Jitter: 5 * time.Millisecond})
+
+	client, err := net.Dial("tcp", p.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	ping := mysqlPacket(0, 0x0e)
+	start := time.Now()
+	client.Write(ping)
+	if _, err := io.ReadFull(client, make([]byte, len(ping))); err != nil {
+		t.Fatal(err)
+	}
+	if rtt := time.Since(start); rtt < 40*time.Millisecond {
+		t.Fatalf("Expected a round trip of at least 40ms, obtained %v", rtt)
+	}
+}
+
+func TestProxyReset(t *testing.T) {
+	p := startEcho(t)
+	p.SetFaults(proxy.Faults{ResetRate: 1})
+
+	client, err := net.Dial("tcp", p.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	client.Write(mysqlPacket(0, 0x0e))
+	if _, err := client.Read(make([]byte, 1)); err == nil {
+		t.Fatalf("Expected the connection to be reset")
+	}
+	if resets := p.Stats().Resets; resets != 1 {
+		t.Fatalf("Expected 1 reset, obtained %d", resets)
+	}
+}
+
+// Iterations without traffic report zeros rather than leaving gaps.
+func TestSamplerReportsZeros(t *testing.T) {
+	server, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	p, err := proxy.Start("127.0.0.1:0", server.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	timer := utils.NewTimer("idle").SetSilent().AddSampler(p.Sampler())
+	timer.TimeIt(func() {})
+	for _, unit := range []string{"wire-sent-B/op", "round-trips/op", "COM_QUERY/op"} {
+		if v := timer.Metric(unit)[0]; v != 0 {
+			t.Errorf("Expected 0 %s, obtained %v", unit, v)
+		}
+	}
+	if units := proxy.Units(timer); slices.Contains(units, "COM_QUERY/op") {
+		t.Errorf("Expected commands that were never sent to be left out, obtained %v", units)
+	}
+}
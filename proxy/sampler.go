@@ -0,0 +1,69 @@
+package proxy
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/podocarp/mysql-test-test/utils"
+)
+
+// Records the traffic through the proxy during each iteration. Traffic from
+// anything else using the proxy at the same time is included. Every known
+// command is reported on every iteration, 0 if it was not sent; Units leaves
+// out those that never were.
+type Sampler struct {
+	proxy  *Proxy
+	before Stats
+}
+
+func (p *Proxy) Sampler() *Sampler {
+	return &Sampler{proxy: p}
+}
+
+func (s *Sampler) Start() {
+	s.before = s.proxy.Stats()
+}
+
+func (s *Sampler) Stop() []utils.Metric {
+	after := s.proxy.Stats()
+	result := []utils.Metric{
+		{Value: float64(after.ClientToServer.Bytes - s.before.ClientToServer.Bytes), Unit: "wire-sent-B/op"},
+		{Value: float64(after.ServerToClient.Bytes - s.before.ServerToClient.Bytes), Unit: "wire-recv-B/op"},
+		{Value: float64(after.ClientToServer.Packets - s.before.ClientToServer.Packets), Unit: "wire-sent-packets/op"},
+		{Value: float64(after.ServerToClient.Packets - s.before.ServerToClient.Packets), Unit: "wire-recv-packets/op"},
+		{Value: float64(after.RoundTrips - s.before.RoundTrips), Unit: "round-trips/op"},
+		{Value: float64(after.Resets - s.before.Resets), Unit: "wire-resets/op"},
+	}
+
+	names := slices.Collect(maps.Values(commandNames))
+	for name := range after.Commands {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		delta := after.Commands[name] - s.before.Commands[name]
+		result = append(result, utils.Metric{Value: float64(delta), Unit: name + "/op"})
+	}
+	return result
+}
+
+// Returns the units of all metrics of the timers that were recorded by a
+// Sampler. Commands that were never sent are left out.
+func Units(timers ...*utils.Timer) []string {
+	units := []string{}
+	for _, t := range timers {
+		for _, unit := range t.Units() {
+			if strings.HasPrefix(unit, "COM_") && !t.NonZero(unit) {
+				continue
+			}
+			if (strings.HasPrefix(unit, "wire-") || strings.HasPrefix(unit, "round-trips") ||
+				strings.HasPrefix(unit, "COM_")) && !slices.Contains(units, unit) {
+				units = append(units, unit)
+			}
+		}
+	}
+	return units
+}
@@ -0,0 +1,57 @@
+package report
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/podocarp/mysql-test-test/db"
+)
+
+// Lists server side statistics per statement. wall is the total client side
+// time spent running the statements, and is used to show how much of it the
+// server spent executing them rather than in the driver or on round trips.
+func DigestTable(title string, digests []db.Digest, wall time.Duration) Table {
+	table := Table{
+		Title: title,
+		Header: []string{
+			"Statement", "Count", "Latency", "Avg latency", "Lock time",
+			"Rows examined", "Rows sent", "Rows affected",
+			"Tmp tables", "Tmp disk tables", "Sort rows", "Sort merge passes", "Sort scans",
+			"No index used",
+		},
+	}
+
+	var server time.Duration
+	for _, d := range digests {
+		server += d.Latency
+		avg := time.Duration(0)
+		if d.Count > 0 {
+			avg = d.Latency / time.Duration(d.Count)
+		}
+		table.Rows = append(table.Rows, []string{
+			d.Text,
+			strconv.FormatInt(d.Count, 10),
+			d.Latency.Round(time.Microsecond).String(),
+			avg.Round(100 * time.Nanosecond).String(),
+			d.LockTime.Round(time.Microsecond).String(),
+			strconv.FormatInt(d.RowsExamined, 10),
+			strconv.FormatInt(d.RowsSent, 10),
+			strconv.FormatInt(d.RowsAffected, 10),
+			strconv.FormatInt(d.TmpTables, 10),
+			strconv.FormatInt(d.TmpDiskTables, 10),
+			strconv.FormatInt(d.SortRows, 10),
+			strconv.FormatInt(d.SortMergePasses, 10),
+			strconv.FormatInt(d.SortScans, 10),
+			strconv.FormatInt(d.NoIndexUsed, 10),
+		})
+	}
+
+	if wall > 0 {
+		table.Notes = append(table.Notes, fmt.Sprintf(
+			"Server execution %v of %v wall time (%s), the rest is spent in the client, driver and on round trips.",
+			server.Round(time.Millisecond), wall.Round(time.Millisecond),
+			formatPercent(100*float64(server)/float64(wall))))
+	}
+	return table
+}
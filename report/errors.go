@@ -0,0 +1,38 @@
+package report
+
+import (
+	"strconv"
+
+	"github.com/podocarp/mysql-test-test/utils"
+)
+
+// Lists how often each timer ran into each class of error, with the last
+// message seen as an example.
+func ErrorTable(title string, timers ...*utils.Timer) Table {
+	table := Table{
+		Title:  title,
+		Header: []string{"Timer", "Class", "Count", "Retried", "Example"},
+	}
+	for _, t := range timers {
+		for _, class := range t.ErrorClasses() {
+			count, retried, example := 0, 0, ""
+			for _, e := range t.Errors() {
+				if e.Class != class {
+					continue
+				}
+				count++
+				if e.Retried {
+					retried++
+				}
+				example = e.Err.Error()
+			}
+			table.Rows = append(table.Rows, []string{
+				t.Name(), class, strconv.Itoa(count), strconv.Itoa(retried), example,
+			})
+		}
+	}
+	if len(table.Rows) == 0 {
+		table.Notes = append(table.Notes, "No errors.")
+	}
+	return table
+}
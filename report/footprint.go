@@ -0,0 +1,81 @@
+package report
+
+import (
+	"strconv"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/opts"
+	"github.com/podocarp/mysql-test-test/db"
+)
+
+// Lists the size of each table, in total and per row.
+func FootprintTable(title string, footprints ...db.Footprint) Table {
+	table := Table{
+		Title: title,
+		Header: []string{
+			"Table", "Engine", "Row format", "Options", "Rows", "Data", "Index",
+			"Free", "Avg row length", ".ibd file", "Allocated", "Bytes/row",
+			".ibd bytes/row",
+		},
+	}
+	for _, f := range footprints {
+		file, allocated, filePerRow := "-", "-", "-"
+		if f.FileSize >= 0 {
+			file = formatBytes(float64(f.FileSize))
+			filePerRow = FormatFloat(f.FileBytesPerRow())
+		}
+		if f.AllocatedSize >= 0 {
+			allocated = formatBytes(float64(f.AllocatedSize))
+		}
+		table.Rows = append(table.Rows, []string{
+			f.Table,
+			f.Engine,
+			f.RowFormat,
+			f.CreateOptions,
+			strconv.FormatInt(f.Rows, 10),
+			formatBytes(float64(f.DataLength)),
+			formatBytes(float64(f.IndexLength)),
+			formatBytes(float64(f.DataFree)),
+			strconv.FormatInt(f.AvgRowLength, 10),
+			file,
+			allocated,
+			FormatFloat(f.BytesPerRow()),
+			filePerRow,
+		})
+	}
+	return table
+}
+
+// Plots bytes per row of each table as bars.
+func FootprintChart(title string, footprints ...db.Footprint) *charts.Bar {
+	chart := charts.NewBar()
+	chart.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{
+			Title: title,
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: "Bytes/row",
+		}),
+	)
+
+	x := make([]string, len(footprints))
+	data := make([]opts.BarData, len(footprints))
+	index := make([]opts.BarData, len(footprints))
+	file := make([]opts.BarData, len(footprints))
+	for i, f := range footprints {
+		x[i] = f.Table
+		if f.Rows == 0 {
+			continue
+		}
+		data[i] = opts.BarData{Value: float64(f.DataLength) / float64(f.Rows)}
+		index[i] = opts.BarData{Value: float64(f.IndexLength) / float64(f.Rows)}
+		if f.FileSize >= 0 {
+			file[i] = opts.BarData{Value: f.FileBytesPerRow()}
+		}
+	}
+	chart.SetXAxis(x)
+	chart.AddSeries("data", data)
+	chart.AddSeries("index", index)
+	chart.AddSeries(".ibd file", file)
+	return chart
+}
@@ -0,0 +1,71 @@
+package report
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/podocarp/mysql-test-test/utils"
+)
+
+// Returns the mean of the values that are not NaN, or NaN if there are none.
+func Mean(values []float64) float64 {
+	sum, n := 0.0, 0
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			sum += v
+			n++
+		}
+	}
+	if n == 0 {
+		return math.NaN()
+	}
+	return sum / float64(n)
+}
+
+// Returns the mean of a timer's metric over the iterations that reported it,
+// or NaN if none did. ms/op is the mean duration of the timed iterations.
+func MetricMean(t *utils.Timer, unit string) float64 {
+	if unit == "ms/op" {
+		if t.Timed() == 0 {
+			return math.NaN()
+		}
+		return float64(t.Mean().Microseconds()) / 1000
+	}
+	return Mean(t.Metric(unit))
+}
+
+// Formats a value with two decimals, or - for NaN.
+func FormatFloat(v float64) string {
+	if math.IsNaN(v) {
+		return "-"
+	}
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+// Lists the mean of each custom metric per iteration and per row, for every
+// timer. Per row values divide by the timer's rows/op metric, e.g.
+// Innodb_data_written/op becomes bytes written per inserted row.
+func MetricTable(title string, units []string, timers ...*utils.Timer) Table {
+	table := Table{
+		Title:  title,
+		Header: []string{"Metric"},
+	}
+	for _, t := range timers {
+		table.Header = append(table.Header, t.Name()+" /op", t.Name()+" /row")
+	}
+
+	for _, unit := range units {
+		row := []string{strings.TrimSuffix(unit, "/op")}
+		for _, t := range timers {
+			values, rows := t.Metric(unit), t.Metric("rows/op")
+			perRow := make([]float64, len(values))
+			for i := range values {
+				perRow[i] = values[i] / rows[i]
+			}
+			row = append(row, FormatFloat(Mean(values)), FormatFloat(Mean(perRow)))
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table
+}
@@ -0,0 +1,46 @@
+package report_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/podocarp/mysql-test-test/report"
+	"github.com/podocarp/mysql-test-test/utils"
+)
+
+func TestMetricTable(t *testing.T) {
+	timers, err := utils.ParseBenchmarks(strings.NewReader(`
+BenchmarkCountries/json-8	1	100 ns/op	1000 rows/op	40000 Innodb_data_written/op
+BenchmarkCountries/json-8	1	100 ns/op	1000 rows/op	60000 Innodb_data_written/op
+BenchmarkCountries/bitset-8	1	100 ns/op	500 rows/op	10000 Innodb_data_written/op
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table := report.MetricTable("status", []string{"Innodb_data_written/op"}, timers...)
+	expected := []string{"Innodb_data_written", "50000.00", "50.00", "10000.00", "20.00"}
+	if strings.Join(table.Rows[0], ",") != strings.Join(expected, ",") {
+		t.Fatalf("Expected %v, obtained %v", expected, table.Rows[0])
+	}
+}
+
+func TestMetricMean(t *testing.T) {
+	timers, err := utils.ParseBenchmarks(strings.NewReader(`
+BenchmarkCountries/json-8	1	1000000 ns/op	10 B/op
+BenchmarkCountries/json-8	1	3000000 ns/op
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ms := report.MetricMean(timers[0], "ms/op"); ms != 2 {
+		t.Fatalf("Expected 2 ms/op, obtained %v", ms)
+	}
+	if b := report.MetricMean(timers[0], "B/op"); b != 10 {
+		t.Fatalf("Expected 10 B/op, obtained %v", b)
+	}
+	if s := report.FormatFloat(report.MetricMean(timers[0], "allocs/op")); s != "-" {
+		t.Fatalf("Expected - for a missing metric, obtained %s", s)
+	}
+}
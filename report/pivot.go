@@ -0,0 +1,45 @@
+package report
+
+import (
+	"math"
+	"slices"
+)
+
+// A value at the given row and column of a pivot table.
+type PivotValue struct {
+	Row   string
+	Col   string
+	Value float64
+}
+
+// Lays values out in a grid, rows and columns in order of first appearance.
+// Values that land in the same spot are averaged, NaN values are left out.
+func PivotTable(title, rowName, colName string, values ...PivotValue) Table {
+	rows, cols := []string{}, []string{}
+	grid := map[[2]string][]float64{}
+	for _, v := range values {
+		if !slices.Contains(rows, v.Row) {
+			rows = append(rows, v.Row)
+		}
+		if !slices.Contains(cols, v.Col) {
+			cols = append(cols, v.Col)
+		}
+		if !math.IsNaN(v.Value) {
+			key := [2]string{v.Row, v.Col}
+			grid[key] = append(grid[key], v.Value)
+		}
+	}
+
+	table := Table{
+		Title:  title,
+		Header: append([]string{rowName + " \\ " + colName}, cols...),
+	}
+	for _, row := range rows {
+		line := []string{row}
+		for _, col := range cols {
+			line = append(line, FormatFloat(Mean(grid[[2]string{row, col}])))
+		}
+		table.Rows = append(table.Rows, line)
+	}
+	return table
+}
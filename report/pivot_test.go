@@ -0,0 +1,28 @@
+package report_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/podocarp/mysql-test-test/report"
+)
+
+func TestPivotTable(t *testing.T) {
+	table := report.PivotTable("ms/op", "rows", "strategy",
+		report.PivotValue{Row: "1000", Col: "json", Value: 10},
+		report.PivotValue{Row: "1000", Col: "json", Value: 20},
+		report.PivotValue{Row: "1000", Col: "bitset", Value: 5},
+		report.PivotValue{Row: "10000", Col: "json", Value: 100},
+		report.PivotValue{Row: "10000", Col: "bitset", Value: math.NaN()},
+	)
+	if strings.Join(table.Header, ",") != `rows \ strategy,json,bitset` {
+		t.Fatalf("Unexpected header %v", table.Header)
+	}
+	if strings.Join(table.Rows[0], ",") != "1000,15.00,5.00" {
+		t.Fatalf("Unexpected first row %v", table.Rows[0])
+	}
+	if strings.Join(table.Rows[1], ",") != "10000,100.00,-" {
+		t.Fatalf("Unexpected second row %v", table.Rows[1])
+	}
+}
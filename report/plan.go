@@ -0,0 +1,40 @@
+package report
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/podocarp/mysql-test-test/db"
+)
+
+// Lists how each query accesses its tables, flagging full table scans and
+// filesorts.
+func PlanTable(title string, plans []db.Plan) Table {
+	table := Table{
+		Title:  title,
+		Header: []string{"Query", "Table", "Access", "Key", "Rows", "Warnings"},
+	}
+	for _, plan := range plans {
+		warnings := []string{}
+		if plan.FullScan {
+			warnings = append(warnings, "⚠ full table scan")
+		}
+		if plan.Filesort {
+			warnings = append(warnings, "⚠ filesort")
+		}
+		for _, access := range plan.Tables {
+			table.Rows = append(table.Rows, []string{
+				plan.Query,
+				access.Table,
+				access.AccessType,
+				access.Key,
+				strconv.FormatInt(access.Rows, 10),
+				strings.Join(warnings, ", "),
+			})
+		}
+		if plan.Analyze != "" {
+			table.Notes = append(table.Notes, plan.Analyze)
+		}
+	}
+	return table
+}
@@ -0,0 +1,262 @@
+package report
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/pprof/profile"
+	"github.com/podocarp/mysql-test-test/utils"
+)
+
+// Flat and cumulative totals of a single function.
+type FuncStat struct {
+	Name string
+	Flat int64
+	Cum  int64
+}
+
+// Function level totals of a profile, for the profile's default sample type.
+type ProfileSummary struct {
+	// Sample type and unit, e.g. cpu and nanoseconds.
+	Type  string
+	Unit  string
+	Total int64
+	// Sorted by flat value, largest first.
+	Funcs []FuncStat
+}
+
+// Parses the profile files and merges them into a single profile.
+func LoadProfiles(filenames ...string) (*profile.Profile, error) {
+	profiles := make([]*profile.Profile, len(filenames))
+	for i, filename := range filenames {
+		f, err := os.Open(filename)
+		if err != nil {
+			return nil, err
+		}
+		p, err := profile.Parse(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+		profiles[i] = p
+	}
+	return profile.Merge(profiles)
+}
+
+// Adds up the samples of a profile per function, the same way `go tool pprof
+// -top` does.
+func Summarize(p *profile.Profile) (ProfileSummary, error) {
+	if len(p.SampleType) == 0 {
+		return ProfileSummary{}, fmt.Errorf("profile has no sample types")
+	}
+	index := len(p.SampleType) - 1
+	if p.DefaultSampleType != "" {
+		for i, st := range p.SampleType {
+			if st.Type == p.DefaultSampleType {
+				index = i
+			}
+		}
+	}
+
+	summary := ProfileSummary{
+		Type: p.SampleType[index].Type,
+		Unit: p.SampleType[index].Unit,
+	}
+	stats := map[string]*FuncStat{}
+	stat := func(name string) *FuncStat {
+		s, ok := stats[name]
+		if !ok {
+			s = &FuncStat{Name: name}
+			stats[name] = s
+		}
+		return s
+	}
+
+	for _, sample := range p.Sample {
+		value := sample.Value[index]
+		summary.Total += value
+
+		seen := map[string]bool{}
+		for i, loc := range sample.Location {
+			for j, line := range loc.Line {
+				name := "?"
+				if line.Function != nil {
+					name = line.Function.Name
+				}
+				// The leaf is the innermost inlined function of the
+				// first location.
+				if i == 0 && j == 0 {
+					stat(name).Flat += value
+				}
+				if !seen[name] {
+					seen[name] = true
+					stat(name).Cum += value
+				}
+			}
+		}
+	}
+
+	for _, s := range stats {
+		summary.Funcs = append(summary.Funcs, *s)
+	}
+	slices.SortFunc(summary.Funcs, func(a, b FuncStat) int {
+		return cmp.Or(cmp.Compare(b.Flat, a.Flat), cmp.Compare(a.Name, b.Name))
+	})
+	return summary, nil
+}
+
+// Returns the n functions with the largest flat value, or cumulative value if
+// byCum is set.
+func (s ProfileSummary) Top(n int, byCum bool) []FuncStat {
+	funcs := slices.Clone(s.Funcs)
+	if byCum {
+		slices.SortStableFunc(funcs, func(a, b FuncStat) int {
+			return cmp.Compare(b.Cum, a.Cum)
+		})
+	}
+	return funcs[:min(n, len(funcs))]
+}
+
+func (s ProfileSummary) percent(value int64) float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return 100 * float64(value) / float64(s.Total)
+}
+
+func (s ProfileSummary) format(value int64) string {
+	switch s.Unit {
+	case "nanoseconds":
+		return time.Duration(value).Round(10 * time.Microsecond).String()
+	case "bytes":
+		return formatBytes(float64(value))
+	}
+	return strconv.FormatInt(value, 10)
+}
+
+func formatBytes(b float64) string {
+	units := []string{"B", "kB", "MB", "GB"}
+	i := 0
+	for math.Abs(b) >= 1024 && i < len(units)-1 {
+		b /= 1024
+		i++
+	}
+	return strconv.FormatFloat(b, 'f', 1, 64) + " " + units[i]
+}
+
+func formatPercent(p float64) string {
+	return strconv.FormatFloat(p, 'f', 2, 64) + "%"
+}
+
+// Lists the top n functions of a profile like `go tool pprof -top`.
+func TopTable(title string, s ProfileSummary, n int, byCum bool) Table {
+	table := Table{
+		Title:  title,
+		Header: []string{"Function", "flat", "flat%", "cum", "cum%"},
+		Notes: []string{fmt.Sprintf("Total %s: %s, sorted by %s",
+			s.Type, s.format(s.Total), map[bool]string{false: "flat", true: "cum"}[byCum])},
+	}
+	for _, f := range s.Top(n, byCum) {
+		table.Rows = append(table.Rows, []string{
+			f.Name,
+			s.format(f.Flat), formatPercent(s.percent(f.Flat)),
+			s.format(f.Cum), formatPercent(s.percent(f.Cum)),
+		})
+	}
+	return table
+}
+
+// Compares two profiles function by function. As the profiles may cover
+// different amounts of work, functions are compared by their share of each
+// profile's total. The n functions whose flat share changed the most are
+// listed.
+func DiffTable(title, baseName string, base ProfileSummary, otherName string, other ProfileSummary, n int) Table {
+	type diff struct {
+		name                string
+		baseFlat, otherFlat float64
+		baseCum, otherCum   float64
+	}
+	diffs := map[string]*diff{}
+	get := func(name string) *diff {
+		d, ok := diffs[name]
+		if !ok {
+			d = &diff{name: name}
+			diffs[name] = d
+		}
+		return d
+	}
+	for _, f := range base.Funcs {
+		d := get(f.Name)
+		d.baseFlat, d.baseCum = base.percent(f.Flat), base.percent(f.Cum)
+	}
+	for _, f := range other.Funcs {
+		d := get(f.Name)
+		d.otherFlat, d.otherCum = other.percent(f.Flat), other.percent(f.Cum)
+	}
+
+	sorted := []*diff{}
+	for _, d := range diffs {
+		sorted = append(sorted, d)
+	}
+	slices.SortFunc(sorted, func(a, b *diff) int {
+		return cmp.Or(
+			cmp.Compare(math.Abs(b.otherFlat-b.baseFlat), math.Abs(a.otherFlat-a.baseFlat)),
+			cmp.Compare(a.name, b.name),
+		)
+	})
+
+	table := Table{
+		Title: title,
+		Header: []string{
+			"Function",
+			baseName + " flat%", otherName + " flat%", "Δ flat%",
+			baseName + " cum%", otherName + " cum%", "Δ cum%",
+		},
+		Notes: []string{fmt.Sprintf("Total %s: %s %s, %s %s",
+			base.Type, baseName, base.format(base.Total), otherName, other.format(other.Total))},
+	}
+	for _, d := range sorted[:min(n, len(sorted))] {
+		table.Rows = append(table.Rows, []string{
+			d.name,
+			formatPercent(d.baseFlat), formatPercent(d.otherFlat), formatPercent(d.otherFlat - d.baseFlat),
+			formatPercent(d.baseCum), formatPercent(d.otherCum), formatPercent(d.otherCum - d.baseCum),
+		})
+	}
+	return table
+}
+
+// Adds tables summarizing the profiles of each strategy found in dir, as
+// written by a utils.ProfileSchedule, plus a diff between the first two
+// strategies. Profiles of all iterations of a strategy are merged.
+func (r *Report) AddProfileSummary(dir string, kind utils.ProfileKind, n int, strategies ...string) error {
+	summaries := make([]ProfileSummary, len(strategies))
+	for i, strategy := range strategies {
+		filenames, err := filepath.Glob(filepath.Join(dir, strategy+"-*."+string(kind)+".out"))
+		if err != nil {
+			return err
+		}
+		if len(filenames) == 0 {
+			return fmt.Errorf("no %s profiles for %s in %s", kind, strategy, dir)
+		}
+		p, err := LoadProfiles(filenames...)
+		if err != nil {
+			return err
+		}
+		if summaries[i], err = Summarize(p); err != nil {
+			return fmt.Errorf("%s: %w", strategy, err)
+		}
+		r.AddTable(TopTable(fmt.Sprintf("%s: top %d by flat %s", strategy, n, kind), summaries[i], n, false))
+		r.AddTable(TopTable(fmt.Sprintf("%s: top %d by cum %s", strategy, n, kind), summaries[i], n, true))
+	}
+	if len(strategies) >= 2 {
+		r.AddTable(DiffTable(fmt.Sprintf("%s vs %s (%s)", strategies[0], strategies[1], kind),
+			strategies[0], summaries[0], strategies[1], summaries[1], n))
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package report_test
+
+import (
+	"testing"
+
+	"github.com/google/pprof/profile"
+	"github.com/podocarp/mysql-test-test/report"
+)
+
+type sample struct {
+	value int64
+	// Function names, leaf first.
+	stack []string
+}
+
+func makeProfile(samples ...sample) *profile.Profile {
+	p := &profile.Profile{
+		SampleType: []*profile.ValueType{
+			{Type: "samples", Unit: "count"},
+			{Type: "cpu", Unit: "nanoseconds"},
+		},
+	}
+	funcs := map[string]*profile.Function{}
+	for _, smp := range samples {
+		sample := &profile.Sample{Value: []int64{1, smp.value}}
+		for _, name := range smp.stack {
+			f, ok := funcs[name]
+			if !ok {
+				f = &profile.Function{ID: uint64(len(funcs) + 1), Name: name}
+				funcs[name] = f
+				p.Function = append(p.Function, f)
+			}
+			loc := &profile.Location{
+				ID:   uint64(len(p.Location) + 1),
+				Line: []profile.Line{{Function: f}},
+			}
+			p.Location = append(p.Location, loc)
+			sample.Location = append(sample.Location, loc)
+		}
+		p.Sample = append(p.Sample, sample)
+	}
+	return p
+}
+
+func TestSummarize(t *testing.T) {
+	p := makeProfile(
+		sample{30, []string{"json.Marshal", "main.write", "main.main"}},
+		sample{60, []string{"syscall.Write", "main.write", "main.main"}},
+		sample{10, []string{"main.main"}},
+	)
+	s, err := report.Summarize(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Type != "cpu" || s.Total != 100 {
+		t.Fatalf("Unexpected summary %+v", s)
+	}
+
+	flat := s.Top(1, false)
+	if flat[0].Name != "syscall.Write" || flat[0].Flat != 60 {
+		t.Fatalf("Unexpected top flat %+v", flat)
+	}
+	cum := s.Top(2, true)
+	if cum[0].Name != "main.main" || cum[0].Cum != 100 || cum[1].Name != "main.write" || cum[1].Cum != 90 {
+		t.Fatalf("Unexpected top cum %+v", cum)
+	}
+}
+
+func TestSummarizeNoSampleTypes(t *testing.T) {
+	if _, err := report.Summarize(&profile.Profile{}); err == nil {
+		t.Fatal("Expected a profile without sample types not to summarize")
+	}
+}
+
+func TestDiffTable(t *testing.T) {
+	base, err := report.Summarize(makeProfile(
+		sample{50, []string{"json.Marshal", "main.main"}},
+		sample{50, []string{"syscall.Write", "main.main"}},
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := report.Summarize(makeProfile(
+		sample{100, []string{"syscall.Write", "main.main"}},
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table := report.DiffTable("diff", "json", base, "bitset", other, 10)
+	if len(table.Rows) != 3 {
+		t.Fatalf("Expected 3 rows, obtained %v", table.Rows)
+	}
+	// Both functions moved by 50 points, ties are broken by name.
+	if table.Rows[0][0] != "json.Marshal" || table.Rows[0][3] != "-50.00%" {
+		t.Fatalf("Unexpected first row %v", table.Rows[0])
+	}
+	if table.Rows[2][0] != "main.main" || table.Rows[2][6] != "0.00%" {
+		t.Fatalf("Unexpected last row %v", table.Rows[2])
+	}
+}
@@ -0,0 +1,128 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+
+	"github.com/go-echarts/go-echarts/v2/render"
+)
+
+// Loaded by every report page. Charts stay blank without network access; the
+// tables, such as the profile summaries, do not need it.
+const echartsJS = "https://go-echarts.github.io/go-echarts-assets/assets/echarts.min.js"
+
+// A chart from go-echarts, e.g. *charts.Line.
+type Chart interface {
+	RenderSnippet() render.ChartSnippet
+}
+
+// A simple table of strings.
+type Table struct {
+	Title  string
+	Header []string
+	Rows   [][]string
+	// Optional notes printed under the table.
+	Notes []string
+}
+
+type section struct {
+	Element template.HTML
+	Script  template.HTML
+	Table   *Table
+}
+
+// A Report is a single html page made of charts and tables, in the order they
+// were added.
+type Report struct {
+	Title string
+	// Shows a banner saying the run was interrupted and the results are
+	// incomplete.
+	Partial  bool
+	sections []section
+}
+
+func New(title string) *Report {
+	return &Report{Title: title}
+}
+
+func (r *Report) AddChart(chart Chart) {
+	snippet := chart.RenderSnippet()
+	r.sections = append(r.sections, section{
+		Element: template.HTML(snippet.Element),
+		Script:  template.HTML(snippet.Script),
+	})
+}
+
+func (r *Report) AddTable(table Table) {
+	r.sections = append(r.sections, section{Table: &table})
+}
+
+var page = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }}</title>
+<script src="{{ .JS }}"></script>
+<style>
+body { font-family: sans-serif; }
+.container { display: flex; justify-content: center; align-items: center; }
+.item { margin: auto; }
+table { border-collapse: collapse; margin: 2em auto; font-size: 0.9em; }
+caption { font-weight: bold; padding: 0.5em; }
+th, td { border: 1px solid #ccc; padding: 0.2em 0.6em; }
+td { font-family: monospace; text-align: right; }
+td:first-child { text-align: left; }
+p.note { text-align: center; color: #666; white-space: pre-wrap; }
+p.partial { text-align: center; background: #fdd; padding: 0.5em; }
+</style>
+</head>
+<body>
+<h1>{{ .Title }}</h1>
+{{- if .Partial }}
+<p class="partial">Partial results: the run was interrupted before all iterations were done.</p>
+{{- end }}
+{{- range .Sections }}
+{{- if .Table }}
+<table>
+<caption>{{ .Table.Title }}</caption>
+<tr>{{ range .Table.Header }}<th>{{ . }}</th>{{ end }}</tr>
+{{- range .Table.Rows }}
+<tr>{{ range . }}<td>{{ . }}</td>{{ end }}</tr>
+{{- end }}
+</table>
+{{- range .Table.Notes }}
+<p class="note">{{ . }}</p>
+{{- end }}
+{{- else }}
+{{ .Element }}
+{{ .Script }}
+{{- end }}
+{{- end }}
+</body>
+</html>
+`))
+
+func (r *Report) Render(w io.Writer) error {
+	return page.Execute(w, struct {
+		Title    string
+		Partial  bool
+		JS       string
+		Sections []section
+	}{r.Title, r.Partial, echartsJS, r.sections})
+}
+
+// Writes the report to an html file.
+func (r *Report) Save(filename string) {
+	f, err := os.Create(filename)
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+
+	if err := r.Render(f); err != nil {
+		panic(err)
+	}
+	fmt.Println("Written report to", filename)
+}
@@ -0,0 +1,57 @@
+package report
+
+import (
+	"slices"
+
+	"github.com/podocarp/mysql-test-test/db"
+)
+
+// The server variables one column of a VariableTable ran with.
+type VariableColumn struct {
+	Name string
+	// Values read back from the server.
+	Values map[string]string
+	// Values the server profile asked for, if any.
+	Want map[string]string
+}
+
+// Lists the server variables each column ran with. Values that differ from
+// what the profile asked for, usually static variables that need a restart,
+// are flagged.
+func VariableTable(title string, columns ...VariableColumn) Table {
+	table := Table{
+		Title:  title,
+		Header: []string{"Variable"},
+	}
+	names := []string{}
+	for _, c := range columns {
+		table.Header = append(table.Header, c.Name)
+		for name := range c.Values {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	slices.Sort(names)
+
+	mismatch := false
+	for _, name := range names {
+		row := []string{name}
+		for _, c := range columns {
+			value, ok := c.Values[name]
+			if !ok {
+				value = "-"
+			}
+			if want, ok := c.Want[name]; ok && !db.SameValue(value, want) {
+				value += " ⚠ want " + want
+				mismatch = true
+			}
+			row = append(row, value)
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	if mismatch {
+		table.Notes = append(table.Notes, "⚠ differs from the server profile. Static variables only change after a restart.")
+	}
+	return table
+}
@@ -0,0 +1,24 @@
+package report_test
+
+import (
+	"testing"
+
+	"github.com/podocarp/mysql-test-test/report"
+)
+
+func TestVariableTable(t *testing.T) {
+	table := report.VariableTable("variables", report.VariableColumn{
+		Name:   "json",
+		Values: map[string]string{"log_bin": "0", "sync_binlog": "1"},
+		Want:   map[string]string{"log_bin": "ON", "sync_binlog": "1"},
+	})
+	if table.Rows[0][0] != "log_bin" || table.Rows[0][1] != "0 ⚠ want ON" {
+		t.Fatalf("Expected log_bin to be flagged, obtained %v", table.Rows[0])
+	}
+	if table.Rows[1][1] != "1" {
+		t.Fatalf("Expected sync_binlog to match, obtained %v", table.Rows[1])
+	}
+	if len(table.Notes) != 1 {
+		t.Fatalf("Expected a note about restarts, obtained %v", table.Notes)
+	}
+}
@@ -0,0 +1,172 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Writes the timers in the standard `go test -bench` text format so the
+// results can be fed to benchstat and friends. Every timed iteration becomes
+// one benchmark line, named Benchmark<prefix>/<timer name>, carrying ns/op and
+// any custom metrics reported on the timer. config is written as extra
+// "key: value" lines in the header, sorted by key, which benchstat uses to
+// label the results, e.g. partial: true. A procs line records the -procs
+// suffix, so that ParseBenchmarks can tell it from names ending in -<number>.
+func WriteBenchmarks(w io.Writer, prefix string, config map[string]string, timers ...*Timer) error {
+	procs := runtime.GOMAXPROCS(0)
+	_, err := fmt.Fprintf(w, "goos: %s\ngoarch: %s\nprocs: %d\n", runtime.GOOS, runtime.GOARCH, procs)
+	if err != nil {
+		return err
+	}
+	for _, key := range slices.Sorted(maps.Keys(config)) {
+		if _, err := fmt.Fprintf(w, "%s: %s\n", key, config[key]); err != nil {
+			return err
+		}
+	}
+
+	for _, t := range timers {
+		name := t.name
+		if prefix != "" {
+			name = prefix + "/" + name
+		}
+
+		metrics := make([][]float64, len(t.units))
+		for i, unit := range t.units {
+			metrics[i] = t.Metric(unit)
+		}
+
+		for i, duration := range t.durations {
+			if duration < 0 {
+				continue
+			}
+			var sb strings.Builder
+			fmt.Fprintf(&sb, "Benchmark%s-%d\t1\t%d ns/op", name, procs, duration.Nanoseconds())
+			for j, unit := range t.units {
+				if math.IsNaN(metrics[j][i]) {
+					continue
+				}
+				fmt.Fprintf(&sb, "\t%s %s", strconv.FormatFloat(metrics[j][i], 'f', -1, 64), unit)
+			}
+			sb.WriteString("\n")
+			if _, err := io.WriteString(w, sb.String()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Saves the timers to a file in the `go test -bench` format. See
+// WriteBenchmarks.
+func SaveBenchmarks(filename, prefix string, config map[string]string, timers ...*Timer) {
+	f, err := os.Create(filename)
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+
+	if err := WriteBenchmarks(f, prefix, config, timers...); err != nil {
+		panic(err)
+	}
+	fmt.Println("Written benchmarks to", filename)
+}
+
+// Reads `go test -bench` output and turns every distinct benchmark into a
+// Timer, in order of first appearance. The Benchmark prefix and -procs suffix
+// are stripped from the names. If a procs line comes before the results, as
+// WriteBenchmarks writes, only that suffix is stripped; otherwise any trailing
+// -<number> is taken to be one, as go test appends it. Each line becomes one iteration; the ns/op
+// value is the duration and every other value/unit pair is reported as a custom
+// metric. Lines that are not benchmark results are ignored.
+func ParseBenchmarks(r io.Reader) ([]*Timer, error) {
+	timers := []*Timer{}
+	byName := map[string]*Timer{}
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	procs := ""
+	for scanner.Scan() {
+		lineNo++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "procs:" {
+			procs = fields[1]
+			continue
+		}
+		if len(fields) < 2 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+		// Lines like "BenchmarkFoo" without results are printed by -v.
+		if _, err := strconv.Atoi(fields[1]); err != nil {
+			continue
+		}
+		if len(fields)%2 != 0 {
+			return nil, fmt.Errorf("line %d: odd number of value/unit fields", lineNo)
+		}
+
+		name := benchmarkName(fields[0], procs)
+		t, ok := byName[name]
+		if !ok {
+			t = NewTimer(name).SetSilent()
+			byName[name] = t
+			timers = append(timers, t)
+		}
+
+		t.durations = append(t.durations, -1)
+		for i := 2; i < len(fields); i += 2 {
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			unit := fields[i+1]
+			if unit == "ns/op" {
+				t.durations[len(t.durations)-1] = time.Duration(value)
+				continue
+			}
+			t.ReportMetric(value, unit)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return timers, nil
+}
+
+// Loads timers from a file in the `go test -bench` format. See
+// ParseBenchmarks.
+func LoadBenchmarks(filename string) []*Timer {
+	f, err := os.Open(filename)
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+
+	timers, err := ParseBenchmarks(f)
+	if err != nil {
+		panic(fmt.Errorf("%s: %w", filename, err))
+	}
+	return timers
+}
+
+// Strips the Benchmark prefix and the -procs suffix, if any. If procs is not
+// empty, the suffix has to be -procs.
+func benchmarkName(s, procs string) string {
+	s = strings.TrimPrefix(s, "Benchmark")
+	if procs != "" {
+		return strings.TrimSuffix(s, "-"+procs)
+	}
+	if i := strings.LastIndexByte(s, '-'); i >= 0 {
+		if _, err := strconv.Atoi(s[i+1:]); err == nil {
+			s = s[:i]
+		}
+	}
+	return s
+}
@@ -0,0 +1,127 @@
+package utils_test
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/podocarp/mysql-test-test/utils"
+)
+
+const benchOutput = `goos: linux
+goarch: amd64
+pkg: github.com/podocarp/mysql-test-test/bench
+BenchmarkCountries
+BenchmarkCountries/json/write-8         	      30	 201000000 ns/op	      1000 rows/op	  524288 B/op
+BenchmarkCountries/json/write-8         	      30	 199000000 ns/op	      1000 rows/op	  524000 B/op
+BenchmarkCountries/bitset/write-8       	      30	 150000000 ns/op	      1000 rows/op
+PASS
+ok  	github.com/podocarp/mysql-test-test/bench	12.345s
+`
+
+func TestParseBenchmarks(t *testing.T) {
+	timers, err := utils.ParseBenchmarks(strings.NewReader(benchOutput))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(timers) != 2 {
+		t.Fatalf("Expected 2 timers, obtained %d", len(timers))
+	}
+	if timers[0].Name() != "Countries/json/write" {
+		t.Fatalf("Unexpected name %q", timers[0].Name())
+	}
+	if units := timers[0].Units(); len(units) != 2 || units[0] != "rows/op" || units[1] != "B/op" {
+		t.Fatalf("Unexpected units %v", units)
+	}
+	allocs := timers[0].Metric("B/op")
+	if allocs[0] != 524288 || allocs[1] != 524000 {
+		t.Fatalf("Unexpected B/op %v", allocs)
+	}
+	if !math.IsNaN(timers[1].Metric("B/op")[0]) {
+		t.Fatalf("Expected missing metric to be NaN")
+	}
+}
+
+func TestBenchmarksRoundTrip(t *testing.T) {
+	timers, err := utils.ParseBenchmarks(strings.NewReader(benchOutput))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := utils.WriteBenchmarks(&buf, "", nil, timers...); err != nil {
+		t.Fatal(err)
+	}
+	again, err := utils.ParseBenchmarks(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var first, second bytes.Buffer
+	utils.WriteBenchmarks(&first, "", nil, timers...)
+	utils.WriteBenchmarks(&second, "", nil, again...)
+	if first.String() != second.String() {
+		t.Fatalf("Expected\n%s\nobtained\n%s", first.String(), second.String())
+	}
+	if !strings.Contains(first.String(), "BenchmarkCountries/json/write-") ||
+		!strings.Contains(first.String(), "\t201000000 ns/op\t1000 rows/op\t524288 B/op\n") {
+		t.Fatalf("Unexpected output\n%s", first.String())
+	}
+}
+
+func TestBenchmarksConfig(t *testing.T) {
+	timers, err := utils.ParseBenchmarks(strings.NewReader(benchOutput))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	config := map[string]string{"partial": "true", "iterations": "30"}
+	if err := utils.WriteBenchmarks(&buf, "", config, timers...); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "\niterations: 30\npartial: true\nBenchmark") {
+		t.Fatalf("Expected sorted config lines before the results\n%s", buf.String())
+	}
+	again, err := utils.ParseBenchmarks(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again) != 2 {
+		t.Fatalf("Expected 2 timers, obtained %d", len(again))
+	}
+}
+
+func TestBenchmarksNumberedNames(t *testing.T) {
+	timer := utils.NewTimer("Countries/rows-1000").SetSilent()
+	timer.TimeIt(func() {})
+
+	var buf bytes.Buffer
+	if err := utils.WriteBenchmarks(&buf, "", nil, timer); err != nil {
+		t.Fatal(err)
+	}
+	again, err := utils.ParseBenchmarks(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again) != 1 || again[0].Name() != "Countries/rows-1000" {
+		t.Fatalf("Expected Countries/rows-1000, obtained %v", again)
+	}
+}
+
+func TestZeroDurations(t *testing.T) {
+	timers, err := utils.ParseBenchmarks(strings.NewReader(`
+BenchmarkCountries/json-8	1	0 ns/op
+BenchmarkCountries/json-8	1	300 ns/op
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := timers[0].Timed(); n != 2 {
+		t.Fatalf("Expected 2 timed iterations, obtained %d", n)
+	}
+	if mean := timers[0].Mean(); mean != 150 {
+		t.Fatalf("Expected a mean of 150ns, obtained %v", mean)
+	}
+}
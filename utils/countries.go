@@ -0,0 +1,163 @@
+package utils
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+//go:generate go run ./gencountries
+
+// A country by its permanent ID, see countryids.csv. The constants are
+// generated into enum.go.
+type Country int64
+
+// A row of gencountries/iso3166.csv.
+type isoData struct {
+	name    string
+	alpha2  string
+	alpha3  string
+	numeric int
+}
+
+// Lookups by code, built from countryData.
+var (
+	countryByAlpha2  = map[string]Country{}
+	countryByAlpha3  = map[string]Country{}
+	countryByNumeric = map[int]Country{}
+)
+
+func init() {
+	for i, data := range countryData {
+		if data.alpha2 == "" {
+			continue
+		}
+		countryByAlpha2[data.alpha2] = Country(i)
+		countryByAlpha3[data.alpha3] = Country(i)
+		countryByNumeric[data.numeric] = Country(i)
+	}
+}
+
+// The ISO data of c, empty if c is retired or out of range.
+func (c Country) data() isoData {
+	if c < 0 || c >= COUNTRY_PLACEHOLDER_LAST {
+		return isoData{}
+	}
+	return countryData[c]
+}
+
+func (c Country) String() string {
+	str := c.data().name
+	if str == "" {
+		str = "Unknown"
+	}
+	return fmt.Sprintf("Country(%s, %d)", str, c)
+}
+
+// Short name, e.g. "Cabo Verde". Empty for unknown and retired countries,
+// as are the codes.
+func (c Country) Name() string {
+	return c.data().name
+}
+
+// ISO 3166-1 alpha-2 code, e.g. "CV".
+func (c Country) Alpha2() string {
+	return c.data().alpha2
+}
+
+// ISO 3166-1 alpha-3 code, e.g. "CPV".
+func (c Country) Alpha3() string {
+	return c.data().alpha3
+}
+
+// ISO 3166-1 numeric code, e.g. 132. 0 if unknown.
+func (c Country) Numeric() int {
+	return c.data().numeric
+}
+
+// Looks up a country by its alpha-2 code, e.g. "CV".
+func CountryByAlpha2(code string) (Country, bool) {
+	c, ok := countryByAlpha2[code]
+	return c, ok
+}
+
+// Looks up a country by its alpha-3 code, e.g. "CPV".
+func CountryByAlpha3(code string) (Country, bool) {
+	c, ok := countryByAlpha3[code]
+	return c, ok
+}
+
+// Looks up a country by its numeric code, e.g. 132.
+func CountryByNumeric(code int) (Country, bool) {
+	c, ok := countryByNumeric[code]
+	return c, ok
+}
+
+// Returns a country that is not retired.
+func RandomCountry() Country {
+	for {
+		if c := Country(rand.Intn(COUNTRY_PLACEHOLDER_LAST)); !c.Retired() {
+			return c
+		}
+	}
+}
+
+// Like RandomCountry, but draws from r so the result can be reproduced.
+func RandomCountryFrom(r *rand.Rand) Country {
+	for {
+		if c := Country(r.Intn(COUNTRY_PLACEHOLDER_LAST)); !c.Retired() {
+			return c
+		}
+	}
+}
+
+type CountryBitset [4]uint64
+
+// A set of countries. Repeated elements will be ignored.
+type Countries []Country
+
+func RandomCountries() Countries {
+	number := rand.Intn(COUNTRY_PLACEHOLDER_LAST)
+	countries := make(Countries, number)
+	for i := range number {
+		countries[i] = RandomCountry()
+	}
+	return countries
+}
+
+// Like RandomCountries, but draws from r so the result can be reproduced.
+func RandomCountriesFrom(r *rand.Rand) Countries {
+	number := r.Intn(COUNTRY_PLACEHOLDER_LAST)
+	countries := make(Countries, number)
+	for i := range number {
+		countries[i] = RandomCountryFrom(r)
+	}
+	return countries
+}
+
+func (c *Countries) ToBitset() CountryBitset {
+	var e CountryBitset
+
+	for _, country := range *c {
+		arrIndex := int(country / 64)
+		bitIndex := int(country % 64)
+		mask := uint64(1) << bitIndex
+		e[arrIndex] = e[arrIndex] | mask
+	}
+	return e
+}
+
+func (c *CountryBitset) ToCountries() Countries {
+	countries := Countries{}
+	for i, elem := range c {
+		bitIndex := 0
+		for elem != 0 {
+			if elem&1 == 1 {
+				country := Country(bitIndex + i*64)
+				countries = append(countries, country)
+			}
+			bitIndex++
+			elem = elem >> 1
+		}
+	}
+	return countries
+}
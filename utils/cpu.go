@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/opts"
+)
+
+// Splits the wall time of every iteration into client CPU (user plus system
+// time of this process), server CPU (see ProcessSampler) and everything else,
+// which is time spent waiting on the network, disk or locks. Values are in
+// milliseconds. Server CPU is 0 and counted as waiting if it was not sampled,
+// and iterations without timing info are NaN.
+func (t *Timer) CPUBreakdown() (client, server, wait []float64) {
+	user, sys, serverCPU := t.Metric("user-cpu-ns/op"), t.Metric("sys-cpu-ns/op"), t.Metric("server-cpu-ns/op")
+	client = make([]float64, len(t.durations))
+	server = make([]float64, len(t.durations))
+	wait = make([]float64, len(t.durations))
+
+	ms := float64(time.Millisecond)
+	for i, duration := range t.durations {
+		if duration < 0 || math.IsNaN(user[i]) {
+			client[i], server[i], wait[i] = math.NaN(), math.NaN(), math.NaN()
+			continue
+		}
+		client[i] = (user[i] + sys[i]) / ms
+		if !math.IsNaN(serverCPU[i]) {
+			server[i] = serverCPU[i] / ms
+		}
+		wait[i] = max(0, float64(duration)/ms-client[i]-server[i])
+	}
+	return client, server, wait
+}
+
+// Plots the CPU breakdown of a timer as stacked bars, one per iteration.
+func CPUBreakdownGraph(title string, t *Timer) *charts.Bar {
+	chart := charts.NewBar()
+	chart.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{
+			Title: title,
+		}),
+		charts.WithXAxisOpts(opts.XAxis{
+			Name: "Iteration",
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: "Time taken (ms)",
+		}),
+	)
+
+	client, server, wait := t.CPUBreakdown()
+	x := make([]string, len(client))
+	for i := range x {
+		x[i] = strconv.Itoa(i)
+	}
+	chart.SetXAxis(x)
+	chart.AddSeries("client CPU", barData(client))
+	chart.AddSeries("server CPU", barData(server))
+	chart.AddSeries("wait", barData(wait))
+	chart.SetSeriesOptions(charts.WithBarChartOpts(opts.BarChart{
+		Stack: "total",
+	}))
+	return chart
+}
+
+func barData(values []float64) []opts.BarData {
+	data := make([]opts.BarData, len(values))
+	for i, value := range values {
+		if !math.IsNaN(value) {
+			data[i] = opts.BarData{Value: value}
+		}
+	}
+	return data
+}
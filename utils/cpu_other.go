@@ -0,0 +1,16 @@
+//go:build !unix
+
+package utils
+
+// Getrusage is not available, so this records nothing.
+type RusageSampler struct{}
+
+func NewRusageSampler() *RusageSampler {
+	return &RusageSampler{}
+}
+
+func (s *RusageSampler) Start() {}
+
+func (s *RusageSampler) Stop() []Metric {
+	return nil
+}
@@ -0,0 +1,28 @@
+//go:build unix
+
+package utils_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/podocarp/mysql-test-test/utils"
+)
+
+func TestCPUBreakdown(t *testing.T) {
+	timer := utils.NewTimer("sleep").SetSilent()
+	timer.TimeIt(func() { time.Sleep(50 * time.Millisecond) })
+
+	client, server, wait := timer.CPUBreakdown()
+	if math.IsNaN(client[0]) || client[0] < 0 {
+		t.Fatalf("Unexpected client CPU %v", client[0])
+	}
+	if server[0] != 0 {
+		t.Fatalf("Expected no server CPU without a ProcessSampler, obtained %v", server[0])
+	}
+	// Sleeping is waiting, not CPU time.
+	if wait[0] < 40 {
+		t.Fatalf("Expected most of the time to be waiting, obtained %v", wait[0])
+	}
+}
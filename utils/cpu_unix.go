@@ -0,0 +1,38 @@
+//go:build unix
+
+package utils
+
+import (
+	"syscall"
+	"time"
+)
+
+// Records the user and system CPU time used by this process during each
+// iteration, via getrusage.
+type RusageSampler struct {
+	user, sys time.Duration
+}
+
+func NewRusageSampler() *RusageSampler {
+	return &RusageSampler{}
+}
+
+func (s *RusageSampler) Start() {
+	s.user, s.sys = rusage()
+}
+
+func (s *RusageSampler) Stop() []Metric {
+	user, sys := rusage()
+	return []Metric{
+		{float64(user - s.user), "user-cpu-ns/op"},
+		{float64(sys - s.sys), "sys-cpu-ns/op"},
+	}
+}
+
+func rusage() (user, sys time.Duration) {
+	var ru syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &ru); err != nil {
+		return 0, 0
+	}
+	return time.Duration(ru.Utime.Nano()), time.Duration(ru.Stime.Nano())
+}
@@ -1,590 +1,539 @@
+// Code generated by gencountries from iso3166.csv and countryids.csv. DO NOT EDIT.
+
 package utils
 
-import (
-	"database/sql/driver"
-	"encoding/binary"
-	"fmt"
-	"math/rand"
+// ISO 3166-1 countries, named after their alpha-2 code. The values are their
+// permanent IDs from countryids.csv.
+const (
+	COUNTRY_AF Country = 0   // Afghanistan
+	COUNTRY_AX Country = 1   // Åland Islands
+	COUNTRY_AL Country = 2   // Albania
+	COUNTRY_DZ Country = 3   // Algeria
+	COUNTRY_AS Country = 4   // American Samoa
+	COUNTRY_AD Country = 5   // Andorra
+	COUNTRY_AO Country = 6   // Angola
+	COUNTRY_AI Country = 7   // Anguilla
+	COUNTRY_AQ Country = 8   // Antarctica
+	COUNTRY_AG Country = 9   // Antigua and Barbuda
+	COUNTRY_AR Country = 10  // Argentina
+	COUNTRY_AM Country = 11  // Armenia
+	COUNTRY_AW Country = 12  // Aruba
+	COUNTRY_AU Country = 13  // Australia
+	COUNTRY_AT Country = 14  // Austria
+	COUNTRY_AZ Country = 15  // Azerbaijan
+	COUNTRY_BS Country = 16  // Bahamas (the)
+	COUNTRY_BH Country = 17  // Bahrain
+	COUNTRY_BD Country = 18  // Bangladesh
+	COUNTRY_BB Country = 19  // Barbados
+	COUNTRY_BY Country = 20  // Belarus
+	COUNTRY_BE Country = 21  // Belgium
+	COUNTRY_BZ Country = 22  // Belize
+	COUNTRY_BJ Country = 23  // Benin
+	COUNTRY_BM Country = 24  // Bermuda
+	COUNTRY_BT Country = 25  // Bhutan
+	COUNTRY_BO Country = 26  // Bolivia (Plurinational State of)
+	COUNTRY_BQ Country = 27  // Bonaire, Sint Eustatius and Saba
+	COUNTRY_BA Country = 28  // Bosnia and Herzegovina
+	COUNTRY_BW Country = 29  // Botswana
+	COUNTRY_BV Country = 30  // Bouvet Island
+	COUNTRY_BR Country = 31  // Brazil
+	COUNTRY_IO Country = 32  // British Indian Ocean Territory (the)
+	COUNTRY_BN Country = 33  // Brunei Darussalam
+	COUNTRY_BG Country = 34  // Bulgaria
+	COUNTRY_BF Country = 35  // Burkina Faso
+	COUNTRY_BI Country = 36  // Burundi
+	COUNTRY_CV Country = 37  // Cabo Verde
+	COUNTRY_KH Country = 38  // Cambodia
+	COUNTRY_CM Country = 39  // Cameroon
+	COUNTRY_CA Country = 40  // Canada
+	COUNTRY_KY Country = 41  // Cayman Islands (the)
+	COUNTRY_CF Country = 42  // Central African Republic (the)
+	COUNTRY_TD Country = 43  // Chad
+	COUNTRY_CL Country = 44  // Chile
+	COUNTRY_CN Country = 45  // China
+	COUNTRY_CX Country = 46  // Christmas Island
+	COUNTRY_CC Country = 47  // Cocos (Keeling) Islands (the)
+	COUNTRY_CO Country = 48  // Colombia
+	COUNTRY_KM Country = 49  // Comoros (the)
+	COUNTRY_CD Country = 50  // Congo (the Democratic Republic of the)
+	COUNTRY_CG Country = 51  // Congo (the)
+	COUNTRY_CK Country = 52  // Cook Islands (the)
+	COUNTRY_CR Country = 53  // Costa Rica
+	COUNTRY_CI Country = 54  // Côte d'Ivoire
+	COUNTRY_HR Country = 55  // Croatia
+	COUNTRY_CU Country = 56  // Cuba
+	COUNTRY_CW Country = 57  // Curaçao
+	COUNTRY_CY Country = 58  // Cyprus
+	COUNTRY_CZ Country = 59  // Czechia
+	COUNTRY_DK Country = 60  // Denmark
+	COUNTRY_DJ Country = 61  // Djibouti
+	COUNTRY_DM Country = 62  // Dominica
+	COUNTRY_DO Country = 63  // Dominican Republic (the)
+	COUNTRY_EC Country = 64  // Ecuador
+	COUNTRY_EG Country = 65  // Egypt
+	COUNTRY_SV Country = 66  // El Salvador
+	COUNTRY_GQ Country = 67  // Equatorial Guinea
+	COUNTRY_ER Country = 68  // Eritrea
+	COUNTRY_EE Country = 69  // Estonia
+	COUNTRY_SZ Country = 70  // Eswatini
+	COUNTRY_ET Country = 71  // Ethiopia
+	COUNTRY_FK Country = 72  // Falkland Islands (the) [Malvinas]
+	COUNTRY_FO Country = 73  // Faroe Islands (the)
+	COUNTRY_FJ Country = 74  // Fiji
+	COUNTRY_FI Country = 75  // Finland
+	COUNTRY_FR Country = 76  // France
+	COUNTRY_GF Country = 77  // French Guiana
+	COUNTRY_PF Country = 78  // French Polynesia
+	COUNTRY_TF Country = 79  // French Southern Territories (the)
+	COUNTRY_GA Country = 80  // Gabon
+	COUNTRY_GM Country = 81  // Gambia (the)
+	COUNTRY_GE Country = 82  // Georgia
+	COUNTRY_DE Country = 83  // Germany
+	COUNTRY_GH Country = 84  // Ghana
+	COUNTRY_GI Country = 85  // Gibraltar
+	COUNTRY_GR Country = 86  // Greece
+	COUNTRY_GL Country = 87  // Greenland
+	COUNTRY_GD Country = 88  // Grenada
+	COUNTRY_GP Country = 89  // Guadeloupe
+	COUNTRY_GU Country = 90  // Guam
+	COUNTRY_GT Country = 91  // Guatemala
+	COUNTRY_GG Country = 92  // Guernsey
+	COUNTRY_GN Country = 93  // Guinea
+	COUNTRY_GW Country = 94  // Guinea-Bissau
+	COUNTRY_GY Country = 95  // Guyana
+	COUNTRY_HT Country = 96  // Haiti
+	COUNTRY_HM Country = 97  // Heard Island and McDonald Islands
+	COUNTRY_HN Country = 98  // Honduras
+	COUNTRY_HK Country = 99  // Hong Kong
+	COUNTRY_HU Country = 100 // Hungary
+	COUNTRY_IS Country = 101 // Iceland
+	COUNTRY_IN Country = 102 // India
+	COUNTRY_ID Country = 103 // Indonesia
+	COUNTRY_IR Country = 104 // Iran (Islamic Republic of)
+	COUNTRY_IQ Country = 105 // Iraq
+	COUNTRY_IE Country = 106 // Ireland
+	COUNTRY_IM Country = 107 // Isle of Man
+	COUNTRY_IL Country = 108 // Israel
+	COUNTRY_IT Country = 109 // Italy
+	COUNTRY_JM Country = 110 // Jamaica
+	COUNTRY_JP Country = 111 // Japan
+	COUNTRY_JE Country = 112 // Jersey
+	COUNTRY_JO Country = 113 // Jordan
+	COUNTRY_KZ Country = 114 // Kazakhstan
+	COUNTRY_KE Country = 115 // Kenya
+	COUNTRY_KI Country = 116 // Kiribati
+	COUNTRY_KP Country = 117 // Korea (the Democratic People's Republic of)
+	COUNTRY_KR Country = 118 // Korea (the Republic of)
+	COUNTRY_KW Country = 119 // Kuwait
+	COUNTRY_KG Country = 120 // Kyrgyzstan
+	COUNTRY_LA Country = 121 // Lao People's Democratic Republic (the)
+	COUNTRY_LV Country = 122 // Latvia
+	COUNTRY_LB Country = 123 // Lebanon
+	COUNTRY_LS Country = 124 // Lesotho
+	COUNTRY_LR Country = 125 // Liberia
+	COUNTRY_LY Country = 126 // Libya
+	COUNTRY_LI Country = 127 // Liechtenstein
+	COUNTRY_LT Country = 128 // Lithuania
+	COUNTRY_LU Country = 129 // Luxembourg
+	COUNTRY_MO Country = 130 // Macao
+	COUNTRY_MG Country = 131 // Madagascar
+	COUNTRY_MW Country = 132 // Malawi
+	COUNTRY_MY Country = 133 // Malaysia
+	COUNTRY_MV Country = 134 // Maldives
+	COUNTRY_ML Country = 135 // Mali
+	COUNTRY_MT Country = 136 // Malta
+	COUNTRY_MH Country = 137 // Marshall Islands (the)
+	COUNTRY_MQ Country = 138 // Martinique
+	COUNTRY_MR Country = 139 // Mauritania
+	COUNTRY_MU Country = 140 // Mauritius
+	COUNTRY_YT Country = 141 // Mayotte
+	COUNTRY_MX Country = 142 // Mexico
+	COUNTRY_FM Country = 143 // Micronesia (Federated States of)
+	COUNTRY_MD Country = 144 // Moldova (the Republic of)
+	COUNTRY_MC Country = 145 // Monaco
+	COUNTRY_MN Country = 146 // Mongolia
+	COUNTRY_ME Country = 147 // Montenegro
+	COUNTRY_MS Country = 148 // Montserrat
+	COUNTRY_MA Country = 149 // Morocco
+	COUNTRY_MZ Country = 150 // Mozambique
+	COUNTRY_MM Country = 151 // Myanmar
+	COUNTRY_NA Country = 152 // Namibia
+	COUNTRY_NR Country = 153 // Nauru
+	COUNTRY_NP Country = 154 // Nepal
+	COUNTRY_NL Country = 155 // Netherlands (Kingdom of the)
+	COUNTRY_NC Country = 156 // New Caledonia
+	COUNTRY_NZ Country = 157 // New Zealand
+	COUNTRY_NI Country = 158 // Nicaragua
+	COUNTRY_NE Country = 159 // Niger (the)
+	COUNTRY_NG Country = 160 // Nigeria
+	COUNTRY_NU Country = 161 // Niue
+	COUNTRY_NF Country = 162 // Norfolk Island
+	COUNTRY_MK Country = 163 // North Macedonia
+	COUNTRY_MP Country = 164 // Northern Mariana Islands (the)
+	COUNTRY_NO Country = 165 // Norway
+	COUNTRY_OM Country = 166 // Oman
+	COUNTRY_PK Country = 167 // Pakistan
+	COUNTRY_PW Country = 168 // Palau
+	COUNTRY_PS Country = 169 // Palestine, State of
+	COUNTRY_PA Country = 170 // Panama
+	COUNTRY_PG Country = 171 // Papua New Guinea
+	COUNTRY_PY Country = 172 // Paraguay
+	COUNTRY_PE Country = 173 // Peru
+	COUNTRY_PH Country = 174 // Philippines (the)
+	COUNTRY_PN Country = 175 // Pitcairn
+	COUNTRY_PL Country = 176 // Poland
+	COUNTRY_PT Country = 177 // Portugal
+	COUNTRY_PR Country = 178 // Puerto Rico
+	COUNTRY_QA Country = 179 // Qatar
+	COUNTRY_RE Country = 180 // Réunion
+	COUNTRY_RO Country = 181 // Romania
+	COUNTRY_RU Country = 182 // Russian Federation (the)
+	COUNTRY_RW Country = 183 // Rwanda
+	COUNTRY_BL Country = 184 // Saint Barthélemy
+	COUNTRY_SH Country = 185 // Saint Helena, Ascension and Tristan da Cunha
+	COUNTRY_KN Country = 186 // Saint Kitts and Nevis
+	COUNTRY_LC Country = 187 // Saint Lucia
+	COUNTRY_MF Country = 188 // Saint Martin (French part)
+	COUNTRY_PM Country = 189 // Saint Pierre and Miquelon
+	COUNTRY_VC Country = 190 // Saint Vincent and the Grenadines
+	COUNTRY_WS Country = 191 // Samoa
+	COUNTRY_SM Country = 192 // San Marino
+	COUNTRY_ST Country = 193 // Sao Tome and Principe
+	COUNTRY_SA Country = 194 // Saudi Arabia
+	COUNTRY_SN Country = 195 // Senegal
+	COUNTRY_RS Country = 196 // Serbia
+	COUNTRY_SC Country = 197 // Seychelles
+	COUNTRY_SL Country = 198 // Sierra Leone
+	COUNTRY_SG Country = 199 // Singapore
+	COUNTRY_SX Country = 200 // Sint Maarten (Dutch part)
+	COUNTRY_SK Country = 201 // Slovakia
+	COUNTRY_SI Country = 202 // Slovenia
+	COUNTRY_SB Country = 203 // Solomon Islands
+	COUNTRY_SO Country = 204 // Somalia
+	COUNTRY_ZA Country = 205 // South Africa
+	COUNTRY_GS Country = 206 // South Georgia and the South Sandwich Islands
+	COUNTRY_SS Country = 207 // South Sudan
+	COUNTRY_ES Country = 208 // Spain
+	COUNTRY_LK Country = 209 // Sri Lanka
+	COUNTRY_SD Country = 210 // Sudan (the)
+	COUNTRY_SR Country = 211 // Suriname
+	COUNTRY_SJ Country = 212 // Svalbard and Jan Mayen
+	COUNTRY_SE Country = 213 // Sweden
+	COUNTRY_CH Country = 214 // Switzerland
+	COUNTRY_SY Country = 215 // Syrian Arab Republic (the)
+	COUNTRY_TJ Country = 216 // Tajikistan
+	COUNTRY_TZ Country = 217 // Tanzania, the United Republic of
+	COUNTRY_TH Country = 218 // Thailand
+	COUNTRY_TL Country = 219 // Timor-Leste
+	COUNTRY_TG Country = 220 // Togo
+	COUNTRY_TK Country = 221 // Tokelau
+	COUNTRY_TO Country = 222 // Tonga
+	COUNTRY_TT Country = 223 // Trinidad and Tobago
+	COUNTRY_TN Country = 224 // Tunisia
+	COUNTRY_TR Country = 225 // Türkiye
+	COUNTRY_TM Country = 226 // Turkmenistan
+	COUNTRY_TC Country = 227 // Turks and Caicos Islands (the)
+	COUNTRY_TV Country = 228 // Tuvalu
+	COUNTRY_UG Country = 229 // Uganda
+	COUNTRY_UA Country = 230 // Ukraine
+	COUNTRY_AE Country = 231 // United Arab Emirates (the)
+	COUNTRY_GB Country = 232 // United Kingdom of Great Britain and Northern Ireland (the)
+	COUNTRY_UM Country = 233 // United States Minor Outlying Islands (the)
+	COUNTRY_US Country = 234 // United States of America (the)
+	COUNTRY_UY Country = 235 // Uruguay
+	COUNTRY_UZ Country = 236 // Uzbekistan
+	COUNTRY_VU Country = 237 // Vanuatu
+	COUNTRY_VE Country = 238 // Venezuela (Bolivarian Republic of)
+	COUNTRY_VN Country = 239 // Viet Nam
+	COUNTRY_VG Country = 240 // Virgin Islands (British)
+	COUNTRY_VI Country = 241 // Virgin Islands (U.S.)
+	COUNTRY_WF Country = 242 // Wallis and Futuna
+	COUNTRY_YE Country = 243 // Yemen
+	COUNTRY_ZM Country = 244 // Zambia
+	COUNTRY_ZW Country = 245 // Zimbabwe
+	COUNTRY_EH Country = 246 // Western Sahara
+	COUNTRY_TW Country = 247 // Taiwan (Province of China)
+	COUNTRY_VA Country = 248 // Holy See (the)
 )
 
-type Country int64
+// One more than the highest ID, retired ones included.
+const COUNTRY_PLACEHOLDER_LAST = 249
 
-// Taken from https://en.wikipedia.org/wiki/List_of_ISO_3166_country_codes
+// Names of constants from before they were all named after the alpha-2 code.
 const (
-	COUNTRY_AF = iota
-	COUNTRY_AX
-	COUNTRY_AL
-	COUNTRY_DZ
-	COUNTRY_AS
-	COUNTRY_AD
-	COUNTRY_AO
-	COUNTRY_AI
-	COUNTRY_ATA
-	COUNTRY_AG
-	COUNTRY_AR
-	COUNTRY_AM
-	COUNTRY_AW
-	COUNTRY_AUS
-	COUNTRY_AT
-	COUNTRY_AZ
-	COUNTRY_BS
-	COUNTRY_BH
-	COUNTRY_BD
-	COUNTRY_BB
-	COUNTRY_BY
-	COUNTRY_BE
-	COUNTRY_BZ
-	COUNTRY_BJ
-	COUNTRY_BM
-	COUNTRY_BT
-	COUNTRY_BO
-	COUNTRY_BQ
-	COUNTRY_BA
-	COUNTRY_BW
-	COUNTRY_BV
-	COUNTRY_BR
-	COUNTRY_IO
-	COUNTRY_BRN
-	COUNTRY_BG
-	COUNTRY_BF
-	COUNTRY_BI
-	COUNTRY_CV
-	COUNTRY_KH
-	COUNTRY_CM
-	COUNTRY_CA
-	COUNTRY_KY
-	COUNTRY_CF
-	COUNTRY_TD
-	COUNTRY_CL
-	COUNTRY_CN
-	COUNTRY_CX
-	COUNTRY_CC
-	COUNTRY_CO
-	COUNTRY_KM
-	COUNTRY_CD
-	COUNTRY_CG
-	COUNTRY_CK
-	COUNTRY_CR
-	COUNTRY_CI
-	COUNTRY_HR
-	COUNTRY_CU
-	COUNTRY_CW
-	COUNTRY_CY
-	COUNTRY_CZ
-	COUNTRY_DK
-	COUNTRY_DJ
-	COUNTRY_DM
-	COUNTRY_DO
-	COUNTRY_EC
-	COUNTRY_EG
-	COUNTRY_SV
-	COUNTRY_GQ
-	COUNTRY_ER
-	COUNTRY_EE
-	COUNTRY_SZ
-	COUNTRY_ET
-	COUNTRY_FLK
-	COUNTRY_FO
-	COUNTRY_FJ
-	COUNTRY_FI
-	COUNTRY_FR
-	COUNTRY_GF
-	COUNTRY_PF
-	COUNTRY_ATF
-	COUNTRY_GA
-	COUNTRY_GM
-	COUNTRY_GE
-	COUNTRY_DE
-	COUNTRY_GH
-	COUNTRY_GI
-	COUNTRY_GR
-	COUNTRY_GL
-	COUNTRY_GD
-	COUNTRY_GP
-	COUNTRY_GU
-	COUNTRY_GT
-	COUNTRY_GG
-	COUNTRY_GN
-	COUNTRY_GW
-	COUNTRY_GY
-	COUNTRY_HT
-	COUNTRY_HM
-	COUNTRY_HN
-	COUNTRY_HK
-	COUNTRY_HU
-	COUNTRY_IS
-	COUNTRY_IN
-	COUNTRY_ID
-	COUNTRY_IR
-	COUNTRY_IQ
-	COUNTRY_IE
-	COUNTRY_IM
-	COUNTRY_IL
-	COUNTRY_IT
-	COUNTRY_JM
-	COUNTRY_JP
-	COUNTRY_JE
-	COUNTRY_JO
-	COUNTRY_KZ
-	COUNTRY_KE
-	COUNTRY_KI
-	COUNTRY_KP
-	COUNTRY_KR
-	COUNTRY_KW
-	COUNTRY_KG
-	COUNTRY_LA
-	COUNTRY_LV
-	COUNTRY_LB
-	COUNTRY_LS
-	COUNTRY_LR
-	COUNTRY_LY
-	COUNTRY_LI
-	COUNTRY_LT
-	COUNTRY_LU
-	COUNTRY_MAC
-	COUNTRY_MG
-	COUNTRY_MW
-	COUNTRY_MY
-	COUNTRY_MV
-	COUNTRY_ML
-	COUNTRY_MT
-	COUNTRY_MH
-	COUNTRY_MQ
-	COUNTRY_MR
-	COUNTRY_MU
-	COUNTRY_YT
-	COUNTRY_MX
-	COUNTRY_FM
-	COUNTRY_MD
-	COUNTRY_MC
-	COUNTRY_MN
-	COUNTRY_ME
-	COUNTRY_MS
-	COUNTRY_MA
-	COUNTRY_MZ
-	COUNTRY_MMR
-	COUNTRY_NA
-	COUNTRY_NR
-	COUNTRY_NP
-	COUNTRY_NL
-	COUNTRY_NC
-	COUNTRY_NZ
-	COUNTRY_NI
-	COUNTRY_NE
-	COUNTRY_NG
-	COUNTRY_NU
-	COUNTRY_NF
-	COUNTRY_MKD
-	COUNTRY_MP
-	COUNTRY_NO
-	COUNTRY_OM
-	COUNTRY_PK
-	COUNTRY_PW
-	COUNTRY_PS
-	COUNTRY_PA
-	COUNTRY_PG
-	COUNTRY_PY
-	COUNTRY_PE
-	COUNTRY_PH
-	COUNTRY_PCN
-	COUNTRY_PL
-	COUNTRY_PT
-	COUNTRY_PR
-	COUNTRY_QA
-	COUNTRY_RE
-	COUNTRY_RO
-	COUNTRY_RU
-	COUNTRY_RW
-	COUNTRY_BL
-	COUNTRY_SH
-	COUNTRY_KN
-	COUNTRY_LC
-	COUNTRY_MF
-	COUNTRY_PM
-	COUNTRY_VC
-	COUNTRY_WS
-	COUNTRY_SM
-	COUNTRY_ST
-	COUNTRY_SA
-	COUNTRY_SN
-	COUNTRY_RS
-	COUNTRY_SC
-	COUNTRY_SL
-	COUNTRY_SG
-	COUNTRY_SX
-	COUNTRY_SK
-	COUNTRY_SI
-	COUNTRY_SB
-	COUNTRY_SO
-	COUNTRY_ZA
-	COUNTRY_GS
-	COUNTRY_SS
-	COUNTRY_ES
-	COUNTRY_LK
-	COUNTRY_SD
-	COUNTRY_SR
-	COUNTRY_SJ
-	COUNTRY_SE
-	COUNTRY_CH
-	COUNTRY_SY
-	COUNTRY_TJ
-	COUNTRY_TZ
-	COUNTRY_TH
-	COUNTRY_TL
-	COUNTRY_TG
-	COUNTRY_TK
-	COUNTRY_TO
-	COUNTRY_TT
-	COUNTRY_TN
-	COUNTRY_TR
-	COUNTRY_TM
-	COUNTRY_TC
-	COUNTRY_TV
-	COUNTRY_UG
-	COUNTRY_UA
-	COUNTRY_AE
-	COUNTRY_GB
-	COUNTRY_UMI
-	COUNTRY_US
-	COUNTRY_UY
-	COUNTRY_UZ
-	COUNTRY_VU
-	COUNTRY_VE
-	COUNTRY_VN
-	COUNTRY_VGB
-	COUNTRY_VI
-	COUNTRY_WF
-	COUNTRY_YE
-	COUNTRY_ZM
-	COUNTRY_ZW
-	COUNTRY_PLACEHOLDER_LAST
+	// Deprecated: use COUNTRY_AQ.
+	COUNTRY_ATA = COUNTRY_AQ
+	// Deprecated: use COUNTRY_TF.
+	COUNTRY_ATF = COUNTRY_TF
+	// Deprecated: use COUNTRY_AU.
+	COUNTRY_AUS = COUNTRY_AU
+	// Deprecated: use COUNTRY_BN.
+	COUNTRY_BRN = COUNTRY_BN
+	// Deprecated: use COUNTRY_FK.
+	COUNTRY_FLK = COUNTRY_FK
+	// Deprecated: use COUNTRY_MO.
+	COUNTRY_MAC = COUNTRY_MO
+	// Deprecated: use COUNTRY_MK.
+	COUNTRY_MKD = COUNTRY_MK
+	// Deprecated: use COUNTRY_MM.
+	COUNTRY_MMR = COUNTRY_MM
+	// Deprecated: use COUNTRY_PN.
+	COUNTRY_PCN = COUNTRY_PN
+	// Deprecated: use COUNTRY_UM.
+	COUNTRY_UMI = COUNTRY_UM
+	// Deprecated: use COUNTRY_VG.
+	COUNTRY_VGB = COUNTRY_VG
 )
 
-var countryToString map[Country]string = map[Country]string{
-	COUNTRY_AF:  "Afghanistan",
-	COUNTRY_AX:  "Åland Islands",
-	COUNTRY_AL:  "Albania",
-	COUNTRY_DZ:  "Algeria",
-	COUNTRY_AS:  "American Samoa",
-	COUNTRY_AD:  "Andorra",
-	COUNTRY_AO:  "Angola",
-	COUNTRY_AI:  "Anguilla",
-	COUNTRY_ATA: "Antarctica ",
-	COUNTRY_AG:  "Antigua and Barbuda",
-	COUNTRY_AR:  "Argentina",
-	COUNTRY_AM:  "Armenia",
-	COUNTRY_AW:  "Aruba",
-	COUNTRY_AUS: "Australia ",
-	COUNTRY_AT:  "Austria",
-	COUNTRY_AZ:  "Azerbaijan",
-	COUNTRY_BS:  "Bahamas (the)",
-	COUNTRY_BH:  "Bahrain",
-	COUNTRY_BD:  "Bangladesh",
-	COUNTRY_BB:  "Barbados",
-	COUNTRY_BY:  "Belarus",
-	COUNTRY_BE:  "Belgium",
-	COUNTRY_BZ:  "Belize",
-	COUNTRY_BJ:  "Benin",
-	COUNTRY_BM:  "Bermuda",
-	COUNTRY_BT:  "Bhutan",
-	COUNTRY_BO:  "Bolivia (Plurinational State of)",
-	COUNTRY_BQ:  "Bonaire;Sint Eustatius;Saba",
-	COUNTRY_BA:  "Bosnia and Herzegovina",
-	COUNTRY_BW:  "Botswana",
-	COUNTRY_BV:  "Bouvet Island",
-	COUNTRY_BR:  "Brazil",
-	COUNTRY_IO:  "British Indian Ocean Territory (the)",
-	COUNTRY_BRN: "Brunei Darussalam ",
-	COUNTRY_BG:  "Bulgaria",
-	COUNTRY_BF:  "Burkina Faso",
-	COUNTRY_BI:  "Burundi",
-	COUNTRY_CV:  "Cabo Verde ",
-	COUNTRY_KH:  "Cambodia",
-	COUNTRY_CM:  "Cameroon",
-	COUNTRY_CA:  "Canada",
-	COUNTRY_KY:  "Cayman Islands (the)",
-	COUNTRY_CF:  "Central African Republic (the)",
-	COUNTRY_TD:  "Chad",
-	COUNTRY_CL:  "Chile",
-	COUNTRY_CN:  "China",
-	COUNTRY_CX:  "Christmas Island",
-	COUNTRY_CC:  "Cocos (Keeling) Islands (the)",
-	COUNTRY_CO:  "Colombia",
-	COUNTRY_KM:  "Comoros (the)",
-	COUNTRY_CD:  "Congo (the Democratic Republic of the)",
-	COUNTRY_CG:  "Congo (the) ",
-	COUNTRY_CK:  "Cook Islands (the)",
-	COUNTRY_CR:  "Costa Rica",
-	COUNTRY_CI:  "Côte d'Ivoire ",
-	COUNTRY_HR:  "Croatia",
-	COUNTRY_CU:  "Cuba",
-	COUNTRY_CW:  "Curaçao",
-	COUNTRY_CY:  "Cyprus",
-	COUNTRY_CZ:  "Czechia ",
-	COUNTRY_DK:  "Denmark",
-	COUNTRY_DJ:  "Djibouti",
-	COUNTRY_DM:  "Dominica",
-	COUNTRY_DO:  "Dominican Republic (the)",
-	COUNTRY_EC:  "Ecuador",
-	COUNTRY_EG:  "Egypt",
-	COUNTRY_SV:  "El Salvador",
-	COUNTRY_GQ:  "Equatorial Guinea",
-	COUNTRY_ER:  "Eritrea",
-	COUNTRY_EE:  "Estonia",
-	COUNTRY_SZ:  "Eswatini ",
-	COUNTRY_ET:  "Ethiopia",
-	COUNTRY_FLK: "Falkland Islands (the)",
-	COUNTRY_FO:  "Faroe Islands (the)",
-	COUNTRY_FJ:  "Fiji",
-	COUNTRY_FI:  "Finland",
-	COUNTRY_FR:  "France",
-	COUNTRY_GF:  "French Guiana",
-	COUNTRY_PF:  "French Polynesia",
-	COUNTRY_ATF: "French Southern Territories (the)",
-	COUNTRY_GA:  "Gabon",
-	COUNTRY_GM:  "Gambia (the)",
-	COUNTRY_GE:  "Georgia",
-	COUNTRY_DE:  "Germany",
-	COUNTRY_GH:  "Ghana",
-	COUNTRY_GI:  "Gibraltar",
-	COUNTRY_GR:  "Greece",
-	COUNTRY_GL:  "Greenland",
-	COUNTRY_GD:  "Grenada",
-	COUNTRY_GP:  "Guadeloupe",
-	COUNTRY_GU:  "Guam",
-	COUNTRY_GT:  "Guatemala",
-	COUNTRY_GG:  "Guernsey",
-	COUNTRY_GN:  "Guinea",
-	COUNTRY_GW:  "Guinea-Bissau",
-	COUNTRY_GY:  "Guyana",
-	COUNTRY_HT:  "Haiti",
-	COUNTRY_HM:  "Heard Island and McDonald Islands",
-	COUNTRY_HN:  "Honduras",
-	COUNTRY_HK:  "Hong Kong",
-	COUNTRY_HU:  "Hungary",
-	COUNTRY_IS:  "Iceland",
-	COUNTRY_IN:  "India",
-	COUNTRY_ID:  "Indonesia",
-	COUNTRY_IR:  "Iran (Islamic Republic of)",
-	COUNTRY_IQ:  "Iraq",
-	COUNTRY_IE:  "Ireland",
-	COUNTRY_IM:  "Isle of Man",
-	COUNTRY_IL:  "Israel",
-	COUNTRY_IT:  "Italy",
-	COUNTRY_JM:  "Jamaica",
-	COUNTRY_JP:  "Japan",
-	COUNTRY_JE:  "Jersey",
-	COUNTRY_JO:  "Jordan",
-	COUNTRY_KZ:  "Kazakhstan",
-	COUNTRY_KE:  "Kenya",
-	COUNTRY_KI:  "Kiribati",
-	COUNTRY_KP:  "Korea (the Democratic People's Republic of)",
-	COUNTRY_KR:  "Korea (the Republic of)",
-	COUNTRY_KW:  "Kuwait",
-	COUNTRY_KG:  "Kyrgyzstan",
-	COUNTRY_LA:  "Lao People's Democratic Republic (the)",
-	COUNTRY_LV:  "Latvia",
-	COUNTRY_LB:  "Lebanon",
-	COUNTRY_LS:  "Lesotho",
-	COUNTRY_LR:  "Liberia",
-	COUNTRY_LY:  "Libya",
-	COUNTRY_LI:  "Liechtenstein",
-	COUNTRY_LT:  "Lithuania",
-	COUNTRY_LU:  "Luxembourg",
-	COUNTRY_MAC: "Macao ",
-	COUNTRY_MG:  "Madagascar",
-	COUNTRY_MW:  "Malawi",
-	COUNTRY_MY:  "Malaysia",
-	COUNTRY_MV:  "Maldives",
-	COUNTRY_ML:  "Mali",
-	COUNTRY_MT:  "Malta",
-	COUNTRY_MH:  "Marshall Islands (the)",
-	COUNTRY_MQ:  "Martinique",
-	COUNTRY_MR:  "Mauritania",
-	COUNTRY_MU:  "Mauritius",
-	COUNTRY_YT:  "Mayotte",
-	COUNTRY_MX:  "Mexico",
-	COUNTRY_FM:  "Micronesia (Federated States of)",
-	COUNTRY_MD:  "Moldova (the Republic of)",
-	COUNTRY_MC:  "Monaco",
-	COUNTRY_MN:  "Mongolia",
-	COUNTRY_ME:  "Montenegro",
-	COUNTRY_MS:  "Montserrat",
-	COUNTRY_MA:  "Morocco",
-	COUNTRY_MZ:  "Mozambique",
-	COUNTRY_MMR: "Myanmar ",
-	COUNTRY_NA:  "Namibia",
-	COUNTRY_NR:  "Nauru",
-	COUNTRY_NP:  "Nepal",
-	COUNTRY_NL:  "Netherlands (Kingdom of the)",
-	COUNTRY_NC:  "New Caledonia",
-	COUNTRY_NZ:  "New Zealand",
-	COUNTRY_NI:  "Nicaragua",
-	COUNTRY_NE:  "Niger (the)",
-	COUNTRY_NG:  "Nigeria",
-	COUNTRY_NU:  "Niue",
-	COUNTRY_NF:  "Norfolk Island",
-	COUNTRY_MKD: "North Macedonia ",
-	COUNTRY_MP:  "Northern Mariana Islands (the)",
-	COUNTRY_NO:  "Norway",
-	COUNTRY_OM:  "Oman",
-	COUNTRY_PK:  "Pakistan",
-	COUNTRY_PW:  "Palau",
-	COUNTRY_PS:  "Palestine, State of",
-	COUNTRY_PA:  "Panama",
-	COUNTRY_PG:  "Papua New Guinea",
-	COUNTRY_PY:  "Paraguay",
-	COUNTRY_PE:  "Peru",
-	COUNTRY_PH:  "Philippines (the)",
-	COUNTRY_PCN: "Pitcairn ",
-	COUNTRY_PL:  "Poland",
-	COUNTRY_PT:  "Portugal",
-	COUNTRY_PR:  "Puerto Rico",
-	COUNTRY_QA:  "Qatar",
-	COUNTRY_RE:  "Réunion",
-	COUNTRY_RO:  "Romania",
-	COUNTRY_RU:  "Russian Federation (the)",
-	COUNTRY_RW:  "Rwanda",
-	COUNTRY_BL:  "Saint Barthélemy",
-	COUNTRY_SH:  "Saint Helena Ascension Island Tristan da Cunha",
-	COUNTRY_KN:  "Saint Kitts and Nevis",
-	COUNTRY_LC:  "Saint Lucia",
-	COUNTRY_MF:  "Saint Martin (French part)",
-	COUNTRY_PM:  "Saint Pierre and Miquelon",
-	COUNTRY_VC:  "Saint Vincent and the Grenadines",
-	COUNTRY_WS:  "Samoa",
-	COUNTRY_SM:  "San Marino",
-	COUNTRY_ST:  "Sao Tome and Principe",
-	COUNTRY_SA:  "Saudi Arabia",
-	COUNTRY_SN:  "Senegal",
-	COUNTRY_RS:  "Serbia",
-	COUNTRY_SC:  "Seychelles",
-	COUNTRY_SL:  "Sierra Leone",
-	COUNTRY_SG:  "Singapore",
-	COUNTRY_SX:  "Sint Maarten (Dutch part)",
-	COUNTRY_SK:  "Slovakia",
-	COUNTRY_SI:  "Slovenia",
-	COUNTRY_SB:  "Solomon Islands",
-	COUNTRY_SO:  "Somalia",
-	COUNTRY_ZA:  "South Africa",
-	COUNTRY_GS:  "South Georgia and the South Sandwich Islands",
-	COUNTRY_SS:  "South Sudan",
-	COUNTRY_ES:  "Spain",
-	COUNTRY_LK:  "Sri Lanka",
-	COUNTRY_SD:  "Sudan (the)",
-	COUNTRY_SR:  "Suriname",
-	COUNTRY_SJ:  "Svalbard Jan Mayen",
-	COUNTRY_SE:  "Sweden",
-	COUNTRY_CH:  "Switzerland",
-	COUNTRY_SY:  "Syrian Arab Republic (the)",
-	COUNTRY_TJ:  "Tajikistan",
-	COUNTRY_TZ:  "Tanzania, the United Republic of",
-	COUNTRY_TH:  "Thailand",
-	COUNTRY_TL:  "Timor-Leste",
-	COUNTRY_TG:  "Togo",
-	COUNTRY_TK:  "Tokelau",
-	COUNTRY_TO:  "Tonga",
-	COUNTRY_TT:  "Trinidad and Tobago",
-	COUNTRY_TN:  "Tunisia",
-	COUNTRY_TR:  "Türkiye",
-	COUNTRY_TM:  "Turkmenistan",
-	COUNTRY_TC:  "Turks and Caicos Islands (the)",
-	COUNTRY_TV:  "Tuvalu",
-	COUNTRY_UG:  "Uganda",
-	COUNTRY_UA:  "Ukraine",
-	COUNTRY_AE:  "United Arab Emirates (the)",
-	COUNTRY_GB:  "United Kingdom of Great Britain and Northern Ireland (the)",
-	COUNTRY_UMI: "United States Minor Outlying Islands (the)",
-	COUNTRY_US:  "United States of America (the)",
-	COUNTRY_UY:  "Uruguay",
-	COUNTRY_UZ:  "Uzbekistan",
-	COUNTRY_VU:  "Vanuatu",
-	COUNTRY_VE:  "Venezuela (Bolivarian Republic of)",
-	COUNTRY_VN:  "Viet Nam",
-	COUNTRY_VGB: "Virgin Islands (British)",
-	COUNTRY_VI:  "Virgin Islands (U.S.) ",
-	COUNTRY_WF:  "Wallis and Futuna",
-	COUNTRY_YE:  "Yemen",
-	COUNTRY_ZM:  "Zambia",
-	COUNTRY_ZW:  "Zimbabwe",
-}
-
-func (c Country) String() string {
-	var str string
-	var ok bool
-	if str, ok = countryToString[c]; !ok {
-		str = "Unknown"
-	}
-	return fmt.Sprintf("Country(%s, %d)", str, c)
-}
-
-func RandomCountry() Country {
-	return Country(rand.Intn(COUNTRY_PLACEHOLDER_LAST))
-}
-
-type CountryBitset [4]uint64
-
-// A set of countries. Repeated elements will be ignored.
-type Countries []Country
-
-func RandomCountries() Countries {
-	number := rand.Intn(COUNTRY_PLACEHOLDER_LAST)
-	countries := make(Countries, number)
-	for i := range number {
-		countries[i] = RandomCountry()
-	}
-	return countries
-}
-
-func (c *Countries) ToBitset() CountryBitset {
-	var e CountryBitset
-
-	for _, country := range *c {
-		arrIndex := int(country / 64)
-		bitIndex := int(country % 64)
-		mask := uint64(1) << bitIndex
-		e[arrIndex] = e[arrIndex] | mask
-	}
-	return e
-}
-
-func (c *CountryBitset) ToCountries() Countries {
-	countries := Countries{}
-	for i, elem := range c {
-		bitIndex := 0
-		for elem != 0 {
-			if elem&1 == 1 {
-				country := Country(bitIndex + i*64)
-				countries = append(countries, country)
-			}
-			bitIndex++
-			elem = elem >> 1
-		}
-	}
-	return countries
-}
-
-func (c *Countries) Value() (driver.Value, error) {
-	b := make([]byte, 32)
-	bitset := c.ToBitset()
-	binary.BigEndian.PutUint64(b[0:8], bitset[0])
-	binary.BigEndian.PutUint64(b[8:16], bitset[1])
-	binary.BigEndian.PutUint64(b[16:24], bitset[2])
-	binary.BigEndian.PutUint64(b[24:32], bitset[3])
-	return b, nil
-}
-
-func (e *Countries) Scan(src any) error {
-	var bitset CountryBitset
-	switch val := src.(type) {
-	case []byte:
-		bitset[0] = binary.BigEndian.Uint64(val[0:8])
-		bitset[1] = binary.BigEndian.Uint64(val[8:16])
-		bitset[2] = binary.BigEndian.Uint64(val[16:24])
-		bitset[3] = binary.BigEndian.Uint64(val[24:32])
-	default:
-		return fmt.Errorf("Unknown type of src: %v", src)
-	}
-	*e = bitset.ToCountries()
-	return nil
+// The ISO 3166-1 data of each country, by ID. Retired IDs are left empty.
+var countryData = [COUNTRY_PLACEHOLDER_LAST]isoData{
+	COUNTRY_AF: {"Afghanistan", "AF", "AFG", 4},
+	COUNTRY_AX: {"Åland Islands", "AX", "ALA", 248},
+	COUNTRY_AL: {"Albania", "AL", "ALB", 8},
+	COUNTRY_DZ: {"Algeria", "DZ", "DZA", 12},
+	COUNTRY_AS: {"American Samoa", "AS", "ASM", 16},
+	COUNTRY_AD: {"Andorra", "AD", "AND", 20},
+	COUNTRY_AO: {"Angola", "AO", "AGO", 24},
+	COUNTRY_AI: {"Anguilla", "AI", "AIA", 660},
+	COUNTRY_AQ: {"Antarctica", "AQ", "ATA", 10},
+	COUNTRY_AG: {"Antigua and Barbuda", "AG", "ATG", 28},
+	COUNTRY_AR: {"Argentina", "AR", "ARG", 32},
+	COUNTRY_AM: {"Armenia", "AM", "ARM", 51},
+	COUNTRY_AW: {"Aruba", "AW", "ABW", 533},
+	COUNTRY_AU: {"Australia", "AU", "AUS", 36},
+	COUNTRY_AT: {"Austria", "AT", "AUT", 40},
+	COUNTRY_AZ: {"Azerbaijan", "AZ", "AZE", 31},
+	COUNTRY_BS: {"Bahamas (the)", "BS", "BHS", 44},
+	COUNTRY_BH: {"Bahrain", "BH", "BHR", 48},
+	COUNTRY_BD: {"Bangladesh", "BD", "BGD", 50},
+	COUNTRY_BB: {"Barbados", "BB", "BRB", 52},
+	COUNTRY_BY: {"Belarus", "BY", "BLR", 112},
+	COUNTRY_BE: {"Belgium", "BE", "BEL", 56},
+	COUNTRY_BZ: {"Belize", "BZ", "BLZ", 84},
+	COUNTRY_BJ: {"Benin", "BJ", "BEN", 204},
+	COUNTRY_BM: {"Bermuda", "BM", "BMU", 60},
+	COUNTRY_BT: {"Bhutan", "BT", "BTN", 64},
+	COUNTRY_BO: {"Bolivia (Plurinational State of)", "BO", "BOL", 68},
+	COUNTRY_BQ: {"Bonaire, Sint Eustatius and Saba", "BQ", "BES", 535},
+	COUNTRY_BA: {"Bosnia and Herzegovina", "BA", "BIH", 70},
+	COUNTRY_BW: {"Botswana", "BW", "BWA", 72},
+	COUNTRY_BV: {"Bouvet Island", "BV", "BVT", 74},
+	COUNTRY_BR: {"Brazil", "BR", "BRA", 76},
+	COUNTRY_IO: {"British Indian Ocean Territory (the)", "IO", "IOT", 86},
+	COUNTRY_BN: {"Brunei Darussalam", "BN", "BRN", 96},
+	COUNTRY_BG: {"Bulgaria", "BG", "BGR", 100},
+	COUNTRY_BF: {"Burkina Faso", "BF", "BFA", 854},
+	COUNTRY_BI: {"Burundi", "BI", "BDI", 108},
+	COUNTRY_CV: {"Cabo Verde", "CV", "CPV", 132},
+	COUNTRY_KH: {"Cambodia", "KH", "KHM", 116},
+	COUNTRY_CM: {"Cameroon", "CM", "CMR", 120},
+	COUNTRY_CA: {"Canada", "CA", "CAN", 124},
+	COUNTRY_KY: {"Cayman Islands (the)", "KY", "CYM", 136},
+	COUNTRY_CF: {"Central African Republic (the)", "CF", "CAF", 140},
+	COUNTRY_TD: {"Chad", "TD", "TCD", 148},
+	COUNTRY_CL: {"Chile", "CL", "CHL", 152},
+	COUNTRY_CN: {"China", "CN", "CHN", 156},
+	COUNTRY_CX: {"Christmas Island", "CX", "CXR", 162},
+	COUNTRY_CC: {"Cocos (Keeling) Islands (the)", "CC", "CCK", 166},
+	COUNTRY_CO: {"Colombia", "CO", "COL", 170},
+	COUNTRY_KM: {"Comoros (the)", "KM", "COM", 174},
+	COUNTRY_CD: {"Congo (the Democratic Republic of the)", "CD", "COD", 180},
+	COUNTRY_CG: {"Congo (the)", "CG", "COG", 178},
+	COUNTRY_CK: {"Cook Islands (the)", "CK", "COK", 184},
+	COUNTRY_CR: {"Costa Rica", "CR", "CRI", 188},
+	COUNTRY_CI: {"Côte d'Ivoire", "CI", "CIV", 384},
+	COUNTRY_HR: {"Croatia", "HR", "HRV", 191},
+	COUNTRY_CU: {"Cuba", "CU", "CUB", 192},
+	COUNTRY_CW: {"Curaçao", "CW", "CUW", 531},
+	COUNTRY_CY: {"Cyprus", "CY", "CYP", 196},
+	COUNTRY_CZ: {"Czechia", "CZ", "CZE", 203},
+	COUNTRY_DK: {"Denmark", "DK", "DNK", 208},
+	COUNTRY_DJ: {"Djibouti", "DJ", "DJI", 262},
+	COUNTRY_DM: {"Dominica", "DM", "DMA", 212},
+	COUNTRY_DO: {"Dominican Republic (the)", "DO", "DOM", 214},
+	COUNTRY_EC: {"Ecuador", "EC", "ECU", 218},
+	COUNTRY_EG: {"Egypt", "EG", "EGY", 818},
+	COUNTRY_SV: {"El Salvador", "SV", "SLV", 222},
+	COUNTRY_GQ: {"Equatorial Guinea", "GQ", "GNQ", 226},
+	COUNTRY_ER: {"Eritrea", "ER", "ERI", 232},
+	COUNTRY_EE: {"Estonia", "EE", "EST", 233},
+	COUNTRY_SZ: {"Eswatini", "SZ", "SWZ", 748},
+	COUNTRY_ET: {"Ethiopia", "ET", "ETH", 231},
+	COUNTRY_FK: {"Falkland Islands (the) [Malvinas]", "FK", "FLK", 238},
+	COUNTRY_FO: {"Faroe Islands (the)", "FO", "FRO", 234},
+	COUNTRY_FJ: {"Fiji", "FJ", "FJI", 242},
+	COUNTRY_FI: {"Finland", "FI", "FIN", 246},
+	COUNTRY_FR: {"France", "FR", "FRA", 250},
+	COUNTRY_GF: {"French Guiana", "GF", "GUF", 254},
+	COUNTRY_PF: {"French Polynesia", "PF", "PYF", 258},
+	COUNTRY_TF: {"French Southern Territories (the)", "TF", "ATF", 260},
+	COUNTRY_GA: {"Gabon", "GA", "GAB", 266},
+	COUNTRY_GM: {"Gambia (the)", "GM", "GMB", 270},
+	COUNTRY_GE: {"Georgia", "GE", "GEO", 268},
+	COUNTRY_DE: {"Germany", "DE", "DEU", 276},
+	COUNTRY_GH: {"Ghana", "GH", "GHA", 288},
+	COUNTRY_GI: {"Gibraltar", "GI", "GIB", 292},
+	COUNTRY_GR: {"Greece", "GR", "GRC", 300},
+	COUNTRY_GL: {"Greenland", "GL", "GRL", 304},
+	COUNTRY_GD: {"Grenada", "GD", "GRD", 308},
+	COUNTRY_GP: {"Guadeloupe", "GP", "GLP", 312},
+	COUNTRY_GU: {"Guam", "GU", "GUM", 316},
+	COUNTRY_GT: {"Guatemala", "GT", "GTM", 320},
+	COUNTRY_GG: {"Guernsey", "GG", "GGY", 831},
+	COUNTRY_GN: {"Guinea", "GN", "GIN", 324},
+	COUNTRY_GW: {"Guinea-Bissau", "GW", "GNB", 624},
+	COUNTRY_GY: {"Guyana", "GY", "GUY", 328},
+	COUNTRY_HT: {"Haiti", "HT", "HTI", 332},
+	COUNTRY_HM: {"Heard Island and McDonald Islands", "HM", "HMD", 334},
+	COUNTRY_HN: {"Honduras", "HN", "HND", 340},
+	COUNTRY_HK: {"Hong Kong", "HK", "HKG", 344},
+	COUNTRY_HU: {"Hungary", "HU", "HUN", 348},
+	COUNTRY_IS: {"Iceland", "IS", "ISL", 352},
+	COUNTRY_IN: {"India", "IN", "IND", 356},
+	COUNTRY_ID: {"Indonesia", "ID", "IDN", 360},
+	COUNTRY_IR: {"Iran (Islamic Republic of)", "IR", "IRN", 364},
+	COUNTRY_IQ: {"Iraq", "IQ", "IRQ", 368},
+	COUNTRY_IE: {"Ireland", "IE", "IRL", 372},
+	COUNTRY_IM: {"Isle of Man", "IM", "IMN", 833},
+	COUNTRY_IL: {"Israel", "IL", "ISR", 376},
+	COUNTRY_IT: {"Italy", "IT", "ITA", 380},
+	COUNTRY_JM: {"Jamaica", "JM", "JAM", 388},
+	COUNTRY_JP: {"Japan", "JP", "JPN", 392},
+	COUNTRY_JE: {"Jersey", "JE", "JEY", 832},
+	COUNTRY_JO: {"Jordan", "JO", "JOR", 400},
+	COUNTRY_KZ: {"Kazakhstan", "KZ", "KAZ", 398},
+	COUNTRY_KE: {"Kenya", "KE", "KEN", 404},
+	COUNTRY_KI: {"Kiribati", "KI", "KIR", 296},
+	COUNTRY_KP: {"Korea (the Democratic People's Republic of)", "KP", "PRK", 408},
+	COUNTRY_KR: {"Korea (the Republic of)", "KR", "KOR", 410},
+	COUNTRY_KW: {"Kuwait", "KW", "KWT", 414},
+	COUNTRY_KG: {"Kyrgyzstan", "KG", "KGZ", 417},
+	COUNTRY_LA: {"Lao People's Democratic Republic (the)", "LA", "LAO", 418},
+	COUNTRY_LV: {"Latvia", "LV", "LVA", 428},
+	COUNTRY_LB: {"Lebanon", "LB", "LBN", 422},
+	COUNTRY_LS: {"Lesotho", "LS", "LSO", 426},
+	COUNTRY_LR: {"Liberia", "LR", "LBR", 430},
+	COUNTRY_LY: {"Libya", "LY", "LBY", 434},
+	COUNTRY_LI: {"Liechtenstein", "LI", "LIE", 438},
+	COUNTRY_LT: {"Lithuania", "LT", "LTU", 440},
+	COUNTRY_LU: {"Luxembourg", "LU", "LUX", 442},
+	COUNTRY_MO: {"Macao", "MO", "MAC", 446},
+	COUNTRY_MG: {"Madagascar", "MG", "MDG", 450},
+	COUNTRY_MW: {"Malawi", "MW", "MWI", 454},
+	COUNTRY_MY: {"Malaysia", "MY", "MYS", 458},
+	COUNTRY_MV: {"Maldives", "MV", "MDV", 462},
+	COUNTRY_ML: {"Mali", "ML", "MLI", 466},
+	COUNTRY_MT: {"Malta", "MT", "MLT", 470},
+	COUNTRY_MH: {"Marshall Islands (the)", "MH", "MHL", 584},
+	COUNTRY_MQ: {"Martinique", "MQ", "MTQ", 474},
+	COUNTRY_MR: {"Mauritania", "MR", "MRT", 478},
+	COUNTRY_MU: {"Mauritius", "MU", "MUS", 480},
+	COUNTRY_YT: {"Mayotte", "YT", "MYT", 175},
+	COUNTRY_MX: {"Mexico", "MX", "MEX", 484},
+	COUNTRY_FM: {"Micronesia (Federated States of)", "FM", "FSM", 583},
+	COUNTRY_MD: {"Moldova (the Republic of)", "MD", "MDA", 498},
+	COUNTRY_MC: {"Monaco", "MC", "MCO", 492},
+	COUNTRY_MN: {"Mongolia", "MN", "MNG", 496},
+	COUNTRY_ME: {"Montenegro", "ME", "MNE", 499},
+	COUNTRY_MS: {"Montserrat", "MS", "MSR", 500},
+	COUNTRY_MA: {"Morocco", "MA", "MAR", 504},
+	COUNTRY_MZ: {"Mozambique", "MZ", "MOZ", 508},
+	COUNTRY_MM: {"Myanmar", "MM", "MMR", 104},
+	COUNTRY_NA: {"Namibia", "NA", "NAM", 516},
+	COUNTRY_NR: {"Nauru", "NR", "NRU", 520},
+	COUNTRY_NP: {"Nepal", "NP", "NPL", 524},
+	COUNTRY_NL: {"Netherlands (Kingdom of the)", "NL", "NLD", 528},
+	COUNTRY_NC: {"New Caledonia", "NC", "NCL", 540},
+	COUNTRY_NZ: {"New Zealand", "NZ", "NZL", 554},
+	COUNTRY_NI: {"Nicaragua", "NI", "NIC", 558},
+	COUNTRY_NE: {"Niger (the)", "NE", "NER", 562},
+	COUNTRY_NG: {"Nigeria", "NG", "NGA", 566},
+	COUNTRY_NU: {"Niue", "NU", "NIU", 570},
+	COUNTRY_NF: {"Norfolk Island", "NF", "NFK", 574},
+	COUNTRY_MK: {"North Macedonia", "MK", "MKD", 807},
+	COUNTRY_MP: {"Northern Mariana Islands (the)", "MP", "MNP", 580},
+	COUNTRY_NO: {"Norway", "NO", "NOR", 578},
+	COUNTRY_OM: {"Oman", "OM", "OMN", 512},
+	COUNTRY_PK: {"Pakistan", "PK", "PAK", 586},
+	COUNTRY_PW: {"Palau", "PW", "PLW", 585},
+	COUNTRY_PS: {"Palestine, State of", "PS", "PSE", 275},
+	COUNTRY_PA: {"Panama", "PA", "PAN", 591},
+	COUNTRY_PG: {"Papua New Guinea", "PG", "PNG", 598},
+	COUNTRY_PY: {"Paraguay", "PY", "PRY", 600},
+	COUNTRY_PE: {"Peru", "PE", "PER", 604},
+	COUNTRY_PH: {"Philippines (the)", "PH", "PHL", 608},
+	COUNTRY_PN: {"Pitcairn", "PN", "PCN", 612},
+	COUNTRY_PL: {"Poland", "PL", "POL", 616},
+	COUNTRY_PT: {"Portugal", "PT", "PRT", 620},
+	COUNTRY_PR: {"Puerto Rico", "PR", "PRI", 630},
+	COUNTRY_QA: {"Qatar", "QA", "QAT", 634},
+	COUNTRY_RE: {"Réunion", "RE", "REU", 638},
+	COUNTRY_RO: {"Romania", "RO", "ROU", 642},
+	COUNTRY_RU: {"Russian Federation (the)", "RU", "RUS", 643},
+	COUNTRY_RW: {"Rwanda", "RW", "RWA", 646},
+	COUNTRY_BL: {"Saint Barthélemy", "BL", "BLM", 652},
+	COUNTRY_SH: {"Saint Helena, Ascension and Tristan da Cunha", "SH", "SHN", 654},
+	COUNTRY_KN: {"Saint Kitts and Nevis", "KN", "KNA", 659},
+	COUNTRY_LC: {"Saint Lucia", "LC", "LCA", 662},
+	COUNTRY_MF: {"Saint Martin (French part)", "MF", "MAF", 663},
+	COUNTRY_PM: {"Saint Pierre and Miquelon", "PM", "SPM", 666},
+	COUNTRY_VC: {"Saint Vincent and the Grenadines", "VC", "VCT", 670},
+	COUNTRY_WS: {"Samoa", "WS", "WSM", 882},
+	COUNTRY_SM: {"San Marino", "SM", "SMR", 674},
+	COUNTRY_ST: {"Sao Tome and Principe", "ST", "STP", 678},
+	COUNTRY_SA: {"Saudi Arabia", "SA", "SAU", 682},
+	COUNTRY_SN: {"Senegal", "SN", "SEN", 686},
+	COUNTRY_RS: {"Serbia", "RS", "SRB", 688},
+	COUNTRY_SC: {"Seychelles", "SC", "SYC", 690},
+	COUNTRY_SL: {"Sierra Leone", "SL", "SLE", 694},
+	COUNTRY_SG: {"Singapore", "SG", "SGP", 702},
+	COUNTRY_SX: {"Sint Maarten (Dutch part)", "SX", "SXM", 534},
+	COUNTRY_SK: {"Slovakia", "SK", "SVK", 703},
+	COUNTRY_SI: {"Slovenia", "SI", "SVN", 705},
+	COUNTRY_SB: {"Solomon Islands", "SB", "SLB", 90},
+	COUNTRY_SO: {"Somalia", "SO", "SOM", 706},
+	COUNTRY_ZA: {"South Africa", "ZA", "ZAF", 710},
+	COUNTRY_GS: {"South Georgia and the South Sandwich Islands", "GS", "SGS", 239},
+	COUNTRY_SS: {"South Sudan", "SS", "SSD", 728},
+	COUNTRY_ES: {"Spain", "ES", "ESP", 724},
+	COUNTRY_LK: {"Sri Lanka", "LK", "LKA", 144},
+	COUNTRY_SD: {"Sudan (the)", "SD", "SDN", 729},
+	COUNTRY_SR: {"Suriname", "SR", "SUR", 740},
+	COUNTRY_SJ: {"Svalbard and Jan Mayen", "SJ", "SJM", 744},
+	COUNTRY_SE: {"Sweden", "SE", "SWE", 752},
+	COUNTRY_CH: {"Switzerland", "CH", "CHE", 756},
+	COUNTRY_SY: {"Syrian Arab Republic (the)", "SY", "SYR", 760},
+	COUNTRY_TJ: {"Tajikistan", "TJ", "TJK", 762},
+	COUNTRY_TZ: {"Tanzania, the United Republic of", "TZ", "TZA", 834},
+	COUNTRY_TH: {"Thailand", "TH", "THA", 764},
+	COUNTRY_TL: {"Timor-Leste", "TL", "TLS", 626},
+	COUNTRY_TG: {"Togo", "TG", "TGO", 768},
+	COUNTRY_TK: {"Tokelau", "TK", "TKL", 772},
+	COUNTRY_TO: {"Tonga", "TO", "TON", 776},
+	COUNTRY_TT: {"Trinidad and Tobago", "TT", "TTO", 780},
+	COUNTRY_TN: {"Tunisia", "TN", "TUN", 788},
+	COUNTRY_TR: {"Türkiye", "TR", "TUR", 792},
+	COUNTRY_TM: {"Turkmenistan", "TM", "TKM", 795},
+	COUNTRY_TC: {"Turks and Caicos Islands (the)", "TC", "TCA", 796},
+	COUNTRY_TV: {"Tuvalu", "TV", "TUV", 798},
+	COUNTRY_UG: {"Uganda", "UG", "UGA", 800},
+	COUNTRY_UA: {"Ukraine", "UA", "UKR", 804},
+	COUNTRY_AE: {"United Arab Emirates (the)", "AE", "ARE", 784},
+	COUNTRY_GB: {"United Kingdom of Great Britain and Northern Ireland (the)", "GB", "GBR", 826},
+	COUNTRY_UM: {"United States Minor Outlying Islands (the)", "UM", "UMI", 581},
+	COUNTRY_US: {"United States of America (the)", "US", "USA", 840},
+	COUNTRY_UY: {"Uruguay", "UY", "URY", 858},
+	COUNTRY_UZ: {"Uzbekistan", "UZ", "UZB", 860},
+	COUNTRY_VU: {"Vanuatu", "VU", "VUT", 548},
+	COUNTRY_VE: {"Venezuela (Bolivarian Republic of)", "VE", "VEN", 862},
+	COUNTRY_VN: {"Viet Nam", "VN", "VNM", 704},
+	COUNTRY_VG: {"Virgin Islands (British)", "VG", "VGB", 92},
+	COUNTRY_VI: {"Virgin Islands (U.S.)", "VI", "VIR", 850},
+	COUNTRY_WF: {"Wallis and Futuna", "WF", "WLF", 876},
+	COUNTRY_YE: {"Yemen", "YE", "YEM", 887},
+	COUNTRY_ZM: {"Zambia", "ZM", "ZMB", 894},
+	COUNTRY_ZW: {"Zimbabwe", "ZW", "ZWE", 716},
+	COUNTRY_EH: {"Western Sahara", "EH", "ESH", 732},
+	COUNTRY_TW: {"Taiwan (Province of China)", "TW", "TWN", 158},
+	COUNTRY_VA: {"Holy See (the)", "VA", "VAT", 336},
 }
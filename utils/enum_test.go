@@ -2,6 +2,7 @@ package utils_test
 
 import (
 	"slices"
+	"strings"
 	"testing"
 
 	"github.com/podocarp/mysql-test-test/utils"
@@ -82,3 +83,39 @@ func FuzzValuerScanner(f *testing.F) {
 		assertCountriesEq(t, countries, countries2)
 	})
 }
+
+func TestCountryCodes(t *testing.T) {
+	cv := utils.COUNTRY_CV
+	if cv.Name() != "Cabo Verde" || cv.Alpha2() != "CV" || cv.Alpha3() != "CPV" || cv.Numeric() != 132 {
+		t.Fatalf("Unexpected data %q %s %s %d", cv.Name(), cv.Alpha2(), cv.Alpha3(), cv.Numeric())
+	}
+	if utils.COUNTRY_UMI != utils.COUNTRY_UM || utils.COUNTRY_ATA != utils.COUNTRY_AQ {
+		t.Fatal("Expected the alpha-3 names to alias the alpha-2 ones")
+	}
+
+	for _, id := range utils.CountryIDs() {
+		c := id.ID
+		if id.Retired {
+			continue
+		}
+		if c.Name() == "" || strings.TrimSpace(c.Name()) != c.Name() {
+			t.Errorf("%d: unexpected name %q", c, c.Name())
+		}
+		if c.Alpha2() != id.Alpha2 {
+			t.Errorf("%d: expected %s, obtained %s", c, id.Alpha2, c.Alpha2())
+		}
+		if found, ok := utils.CountryByAlpha2(c.Alpha2()); !ok || found != c {
+			t.Errorf("%s: alpha-2 lookup gave %v", c.Alpha2(), found)
+		}
+		if found, ok := utils.CountryByAlpha3(c.Alpha3()); !ok || found != c {
+			t.Errorf("%s: alpha-3 lookup gave %v", c.Alpha3(), found)
+		}
+		if found, ok := utils.CountryByNumeric(c.Numeric()); !ok || found != c {
+			t.Errorf("%d: numeric lookup gave %v", c.Numeric(), found)
+		}
+	}
+
+	if s := utils.Country(1000).String(); s != "Country(Unknown, 1000)" {
+		t.Fatalf("Unexpected %s", s)
+	}
+}
@@ -0,0 +1,154 @@
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// What TimeItErr does when the timed function fails.
+type ErrorPolicy int
+
+const (
+	// Stop, TimeItErr returns the error.
+	ErrorAbort ErrorPolicy = iota
+	// Record the error and carry on with the next iteration.
+	ErrorSkip
+	// Run the function again, up to the number of retries given to
+	// SetErrorPolicy, then record the error and carry on.
+	ErrorRetry
+)
+
+// An error returned by the function timed in an iteration.
+type IterationError struct {
+	// 1-based, like ProfileSchedule.Iterations.
+	Iteration int
+	// As given by the timer's classifier, e.g. deadlock.
+	Class string
+	Err   error
+	// Whether the iteration was run again after this error.
+	Retried bool
+}
+
+// Sets what TimeItErr does when the timed function fails. retries is only used
+// by ErrorRetry.
+func (t *Timer) SetErrorPolicy(policy ErrorPolicy, retries int) *Timer {
+	t.errorPolicy = policy
+	t.retries = retries
+	return t
+}
+
+// Sets the function used to put errors into classes, e.g. deadlock or
+// connection lost. By default all errors are in the class "error".
+func (t *Timer) SetErrorClassifier(classify func(error) string) *Timer {
+	t.classify = classify
+	return t
+}
+
+// Like TimeIt, but for functions that can fail. Failed iterations have no
+// timing info and are handled according to the timer's error policy. Every
+// iteration reports the number of errors it ran into as errors/op, and with
+// ErrorRetry the number of retries as retries/op. Returns an error only if the
+// policy is ErrorAbort.
+func (t *Timer) TimeItErr(fun func() error) error {
+	return t.TimeItRetry(nil, fun)
+}
+
+// Like TimeItErr, but with ErrorRetry runs prepare, untimed, before every
+// retry, e.g. to undo what the failed attempt left behind. Every attempt is
+// timed on its own and failed ones are discarded, so the iteration's duration
+// and metrics are those of its last attempt; the time failed attempts took is
+// reported as retry-ns/op instead. If prepare fails, the iteration is left
+// untimed and prepare's error is returned whatever the policy.
+func (t *Timer) TimeItRetry(prepare func() error, fun func() error) error {
+	iteration := len(t.durations) + 1
+	var err error
+	retried := []error{}
+	wasted := time.Duration(0)
+	for {
+		t.TimeIt(func() { err = fun() })
+		if err == nil || t.errorPolicy != ErrorRetry || len(retried) >= t.retries {
+			break
+		}
+		if !t.Untimed() {
+			wasted += t.durations[len(t.durations)-1]
+		}
+		retried = append(retried, err)
+		if prepare != nil {
+			if err := prepare(); err != nil {
+				t.durations[len(t.durations)-1] = -1
+				for _, e := range retried {
+					t.recordError(iteration, e, true)
+				}
+				return err
+			}
+		}
+		t.Discard()
+	}
+
+	// Recorded now, Discard would have dropped them.
+	errs := len(retried)
+	for _, e := range retried {
+		t.recordError(iteration, e, true)
+	}
+	if err != nil {
+		errs++
+	}
+	t.ReportMetric(float64(errs), "errors/op")
+	if t.errorPolicy == ErrorRetry {
+		t.ReportMetric(float64(len(retried)), "retries/op")
+		t.ReportMetric(float64(wasted.Nanoseconds()), "retry-ns/op")
+	}
+	if err == nil {
+		return nil
+	}
+
+	t.durations[len(t.durations)-1] = -1
+	t.recordError(iteration, err, false)
+	if t.errorPolicy == ErrorAbort {
+		return err
+	}
+	return nil
+}
+
+func (t *Timer) recordError(iteration int, err error, retried bool) {
+	class := "error"
+	if t.classify != nil {
+		class = t.classify(err)
+	}
+	t.errors = append(t.errors, IterationError{
+		Iteration: iteration,
+		Class:     class,
+		Err:       err,
+		Retried:   retried,
+	})
+	if !t.silent {
+		fmt.Printf("%s iteration %d failed (%s): %v\n", t.name, iteration, class, err)
+	}
+}
+
+// Returns all errors recorded by TimeItErr, in order.
+func (t *Timer) Errors() []IterationError {
+	return t.errors
+}
+
+// Counts the recorded errors by class.
+func (t *Timer) ErrorCounts() map[string]int {
+	counts := map[string]int{}
+	for _, e := range t.errors {
+		counts[e.Class]++
+	}
+	return counts
+}
+
+// Returns the classes of the recorded errors, sorted.
+func (t *Timer) ErrorClasses() []string {
+	classes := []string{}
+	for _, e := range t.errors {
+		if !slices.Contains(classes, e.Class) {
+			classes = append(classes, e.Class)
+		}
+	}
+	slices.Sort(classes)
+	return classes
+}
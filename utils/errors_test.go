@@ -0,0 +1,133 @@
+package utils_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/podocarp/mysql-test-test/utils"
+)
+
+// Returns a function that fails the given number of times before succeeding.
+func failing(times int) func() error {
+	return func() error {
+		if times > 0 {
+			times--
+			return errors.New("boom")
+		}
+		return nil
+	}
+}
+
+func TestTimeItErrPolicies(t *testing.T) {
+	abort := utils.NewTimer("abort").SetSilent()
+	if err := abort.TimeItErr(failing(1)); err == nil {
+		t.Fatalf("Expected ErrorAbort to return the error")
+	}
+	if !abort.Untimed() || len(abort.Errors()) != 1 {
+		t.Fatalf("Expected one untimed failed iteration")
+	}
+
+	skip := utils.NewTimer("skip").SetSilent().SetErrorPolicy(utils.ErrorSkip, 0).
+		SetErrorClassifier(func(error) string { return "boom" })
+	if err := skip.TimeItErr(failing(1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := skip.TimeItErr(failing(0)); err != nil {
+		t.Fatal(err)
+	}
+	if counts := skip.ErrorCounts(); counts["boom"] != 1 {
+		t.Fatalf("Unexpected error counts %v", counts)
+	}
+	if errs := skip.Metric("errors/op"); errs[0] != 1 || errs[1] != 0 {
+		t.Fatalf("Unexpected errors/op %v", errs)
+	}
+
+	retry := utils.NewTimer("retry").SetSilent().SetErrorPolicy(utils.ErrorRetry, 2)
+	if err := retry.TimeItErr(failing(2)); err != nil {
+		t.Fatal(err)
+	}
+	if retry.Untimed() {
+		t.Fatalf("Expected the retried iteration to succeed")
+	}
+	if err := retry.TimeItErr(failing(3)); err != nil {
+		t.Fatal(err)
+	}
+	if !retry.Untimed() {
+		t.Fatalf("Expected the iteration to fail after 2 retries")
+	}
+	if retries := retry.Metric("retries/op"); retries[0] != 2 || retries[1] != 2 {
+		t.Fatalf("Unexpected retries/op %v", retries)
+	}
+	if errs := retry.Errors(); len(errs) != 5 || errs[4].Retried || !errs[3].Retried {
+		t.Fatalf("Unexpected errors %+v", errs)
+	}
+}
+
+func TestDiscard(t *testing.T) {
+	timer := utils.NewTimer("discard").SetSilent().SetErrorPolicy(utils.ErrorSkip, 0)
+	timer.TimeItErr(failing(0))
+	timer.ReportMetric(1000, "rows/op")
+	timer.TimeItErr(failing(1))
+	timer.ReportMetric(1000, "rows/op")
+	timer.ReportMetric(1, "cut-short/op")
+
+	timer.Discard()
+	if len(timer.Errors()) != 0 {
+		t.Fatalf("Expected the errors of the discarded iteration to be dropped, obtained %v", timer.Errors())
+	}
+	if rows := timer.Metric("rows/op"); len(rows) != 1 || rows[0] != 1000 {
+		t.Fatalf("Unexpected rows/op %v", rows)
+	}
+	for _, unit := range timer.Units() {
+		if unit == "cut-short/op" {
+			t.Fatalf("Expected units only reported by the discarded iteration to be dropped")
+		}
+	}
+	if timer.Untimed() {
+		t.Fatalf("Expected the first iteration to be the most recent one")
+	}
+}
+
+// Only the attempt that succeeded is timed, after prepare ran again.
+func TestTimeItRetry(t *testing.T) {
+	timer := utils.NewTimer("retry").SetSilent().SetErrorPolicy(utils.ErrorRetry, 2)
+	attempts, prepared := 0, 0
+	err := timer.TimeItRetry(
+		func() error {
+			prepared++
+			return nil
+		},
+		func() error {
+			attempts++
+			if attempts == 1 {
+				time.Sleep(50 * time.Millisecond)
+				return errors.New("boom")
+			}
+			return nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 2 || prepared != 1 {
+		t.Fatalf("Expected 2 attempts and 1 prepare, obtained %d and %d", attempts, prepared)
+	}
+	if timer.Timed() != 1 {
+		t.Fatalf("Expected one timed iteration, obtained %d", timer.Timed())
+	}
+	if mean := timer.Mean(); mean >= 50*time.Millisecond {
+		t.Fatalf("Expected the failed attempt to be left out of the duration, obtained %v", mean)
+	}
+	if wasted := timer.Metric("retry-ns/op")[0]; wasted < float64(50*time.Millisecond) {
+		t.Fatalf("Expected the failed attempt's time in retry-ns/op, obtained %v", wasted)
+	}
+	if errs := timer.Errors(); len(errs) != 1 || !errs[0].Retried || errs[0].Iteration != 1 {
+		t.Fatalf("Unexpected errors %+v", errs)
+	}
+
+	failing := errors.New("prepare failed")
+	err = timer.TimeItRetry(func() error { return failing }, func() error { return errors.New("boom") })
+	if err != failing || !timer.Untimed() {
+		t.Fatalf("Expected a failed prepare to be returned and leave the iteration untimed, obtained %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+package utils
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+)
+
+// How Countries are stored in a column.
+type Format int
+
+const (
+	// 32 bytes, the CountryBitset words in big endian order. Only holds
+	// countries below 256.
+	FormatBitset Format = iota
+	// A JSON array of country numbers, e.g. [3,40].
+	FormatJSON
+	// Versioned binary encodings with a header, see version1 and version2.
+	FormatBinaryV1
+	FormatBinaryV2
+)
+
+// Format Countries.Value writes. Scan reads every format whatever this is set
+// to, so code can switch to reading a new format before the column is
+// converted, and to writing it once everything reads it. Set it once at
+// startup, it is not safe to change while queries run.
+var ValueFormat = FormatBitset
+
+func (f Format) String() string {
+	switch f {
+	case FormatBitset:
+		return "bitset"
+	case FormatJSON:
+		return "json"
+	case FormatBinaryV1:
+		return "binary-v1"
+	case FormatBinaryV2:
+		return "binary-v2"
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// Writes the countries in ValueFormat.
+func (c *Countries) Value() (driver.Value, error) {
+	return c.Encode(ValueFormat)
+}
+
+// Returns the countries in the given format, e.g. to write one column in a
+// different format than ValueFormat.
+func (c *Countries) Encode(f Format) (driver.Value, error) {
+	switch f {
+	case FormatBitset:
+		for _, country := range *c {
+			if country < 0 || country >= 256 {
+				return nil, fmt.Errorf("%v does not fit in a 32 byte bitset", country)
+			}
+		}
+		b := make([]byte, 32)
+		bitset := c.ToBitset()
+		binary.BigEndian.PutUint64(b[0:8], bitset[0])
+		binary.BigEndian.PutUint64(b[8:16], bitset[1])
+		binary.BigEndian.PutUint64(b[16:24], bitset[2])
+		binary.BigEndian.PutUint64(b[24:32], bitset[3])
+		return b, nil
+	case FormatJSON:
+		if *c == nil {
+			return "[]", nil
+		}
+		j, err := json.Marshal(*c)
+		return string(j), err
+	case FormatBinaryV1:
+		return encodeVersioned(version1, *c)
+	case FormatBinaryV2:
+		return encodeVersioned(version2, *c)
+	}
+	return nil, fmt.Errorf("unknown format %v", f)
+}
+
+// Reads countries in any format, telling them apart by their content: NULL
+// is no countries, text starting with [ is a JSON array, values starting with
+// the versioned header are decoded by their version and other 32 byte values
+// are a bitset. Trailing zeros, as a BINARY(32) column pads shorter values
+// with, are ignored. A bitset whose bytes happen to form a JSON array of
+// numbers or a versioned value is read as such, which would take every byte
+// before the zeros to be a digit, comma, space or bracket, or the first two
+// to be 0xCB and a version with a length matching the rest.
+func (e *Countries) Scan(src any) error {
+	var b []byte
+	switch val := src.(type) {
+	case nil:
+		*e = nil
+		return nil
+	case []byte:
+		b = val
+	case string:
+		b = []byte(val)
+	default:
+		return fmt.Errorf("Unknown type of src: %v", src)
+	}
+
+	switch format, err := DetectFormat(b); {
+	case err != nil:
+		return err
+	case format == FormatJSON:
+		var countries Countries
+		if err := json.Unmarshal(bytes.TrimRight(b, "\x00"), &countries); err != nil {
+			return err
+		}
+		*e = countries
+	case format != FormatBitset:
+		countries, err := decodeVersioned(b)
+		if err != nil {
+			return err
+		}
+		*e = countries
+	default:
+		var bitset CountryBitset
+		bitset[0] = binary.BigEndian.Uint64(b[0:8])
+		bitset[1] = binary.BigEndian.Uint64(b[8:16])
+		bitset[2] = binary.BigEndian.Uint64(b[16:24])
+		bitset[3] = binary.BigEndian.Uint64(b[24:32])
+		*e = bitset.ToCountries()
+	}
+	return nil
+}
+
+// Tells which format a stored value is in, see Countries.Scan.
+func DetectFormat(b []byte) (Format, error) {
+	unpadded := bytes.TrimRight(b, "\x00")
+	trimmed := bytes.TrimSpace(unpadded)
+	isJSON := len(trimmed) > 0 && trimmed[0] == '[' || bytes.Equal(trimmed, []byte("null"))
+	if isJSON && (len(b) != 32 || json.Valid(unpadded)) {
+		return FormatJSON, nil
+	}
+	if len(b) >= versionHeader && b[0] == versionMagic {
+		format, err := versionedFormat(b[1])
+		if len(b) != 32 {
+			return format, err
+		}
+		// Bitsets can start with the header too, so at 32 bytes only a value
+		// that decodes is taken as versioned.
+		if _, decodeErr := decodeVersioned(b); err == nil && decodeErr == nil {
+			return format, nil
+		}
+	}
+	if len(b) == 32 {
+		return FormatBitset, nil
+	}
+	return 0, fmt.Errorf("%d bytes are neither a JSON array, a bitset nor a versioned encoding", len(b))
+}
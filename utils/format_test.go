@@ -0,0 +1,106 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/podocarp/mysql-test-test/utils"
+)
+
+func TestScanFormats(t *testing.T) {
+	countries := utils.Countries{utils.COUNTRY_AD, utils.COUNTRY_FR, utils.COUNTRY_NU}
+	bitset, err := countries.Encode(utils.FormatBitset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, err := countries.Encode(utils.FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "[5,76,161]" {
+		t.Fatalf("Unexpected JSON %v", text)
+	}
+
+	for _, src := range []any{bitset, text, []byte(text.(string)), " [5, 76, 161] "} {
+		var scanned utils.Countries
+		if err := scanned.Scan(src); err != nil {
+			t.Fatalf("%v: %v", src, err)
+		}
+		assertCountriesEq(t, countries, scanned)
+	}
+
+	for _, src := range []any{nil, "null", []byte("[]")} {
+		scanned := utils.Countries{utils.COUNTRY_AD}
+		if err := scanned.Scan(src); err != nil {
+			t.Fatalf("%v: %v", src, err)
+		}
+		if len(scanned) != 0 {
+			t.Fatalf("%v: expected no countries, obtained %v", src, scanned)
+		}
+	}
+
+	for _, src := range []any{[]byte{1, 2, 3}, "{}", 42} {
+		var scanned utils.Countries
+		if err := scanned.Scan(src); err == nil {
+			t.Errorf("Expected %v not to scan", src)
+		}
+	}
+}
+
+// A bitset that starts with the byte for [ is still a bitset.
+func TestScanBitsetLikeJSON(t *testing.T) {
+	b := make([]byte, 32)
+	b[0] = '['
+	b[31] = ']'
+	var countries utils.Countries
+	if err := countries.Scan(b); err != nil {
+		t.Fatal(err)
+	}
+	if format, _ := utils.DetectFormat(b); format != utils.FormatBitset {
+		t.Fatalf("Expected a bitset, obtained %v", format)
+	}
+	if len(countries) != 5+5 {
+		t.Fatalf("Expected the bits of [ and ] to be read, obtained %v", countries)
+	}
+}
+
+// Values a BINARY(32) column pads with zeros are still read in their own
+// format rather than as a bitset.
+func TestScanPadded(t *testing.T) {
+	countries := utils.Countries{utils.COUNTRY_AD, utils.COUNTRY_FR, utils.COUNTRY_NU}
+	for _, format := range []utils.Format{utils.FormatJSON, utils.FormatBinaryV1, utils.FormatBinaryV2} {
+		v, err := countries.Encode(format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b := make([]byte, 32)
+		switch v := v.(type) {
+		case []byte:
+			copy(b, v)
+		case string:
+			copy(b, v)
+		}
+
+		if detected, err := utils.DetectFormat(b); err != nil || detected != format {
+			t.Errorf("%v: detected as %v, %v", format, detected, err)
+		}
+		var scanned utils.Countries
+		if err := scanned.Scan(b); err != nil {
+			t.Fatalf("%v: %v", format, err)
+		}
+		assertCountriesEq(t, countries, scanned)
+	}
+}
+
+func TestValueFormat(t *testing.T) {
+	defer func(f utils.Format) { utils.ValueFormat = f }(utils.ValueFormat)
+	utils.ValueFormat = utils.FormatJSON
+
+	countries := utils.Countries{utils.COUNTRY_FR}
+	v, err := countries.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "[76]" {
+		t.Fatalf("Expected a JSON array, obtained %v", v)
+	}
+}
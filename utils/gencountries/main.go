@@ -0,0 +1,241 @@
+// Command gencountries generates utils/enum.go from the ISO 3166-1 list in
+// iso3166.csv, which is embedded, and the permanent IDs in
+// utils/countryids.csv. Run it with `go generate ./utils`.
+//
+// Countries of the list that have no ID yet are given the next free ones,
+// which are appended to countryids.csv. Countries with an ID that are no
+// longer in the list stop the generator until they are marked retired.
+//
+// It does not import utils, so it still runs when enum.go is broken; the
+// registry is read by utils/internal/countryids, like utils does.
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/podocarp/mysql-test-test/utils/internal/countryids"
+)
+
+//go:embed iso3166.csv
+var isoCSV []byte
+
+// Constants that were named after the alpha-3 code before the enum was
+// generated, and the alpha-2 code they are now named after.
+var aliases = map[string]string{
+	"ATA": "AQ",
+	"ATF": "TF",
+	"AUS": "AU",
+	"BRN": "BN",
+	"FLK": "FK",
+	"MAC": "MO",
+	"MKD": "MK",
+	"MMR": "MM",
+	"PCN": "PN",
+	"UMI": "UM",
+	"VGB": "VG",
+}
+
+// Active IDs have to stay below this while utils.FormatBitset, a 256 bit set,
+// is the default utils.ValueFormat.
+const bitsetIDs = 256
+
+// A row of iso3166.csv.
+type isoCountry struct {
+	Name    string
+	Alpha2  string
+	Alpha3  string
+	Numeric int
+}
+
+// What the template is run with.
+type enumData struct {
+	// Active countries in ID order.
+	Countries []generated
+	Aliases   []alias
+	Last      int
+}
+
+type generated struct {
+	ID int
+	isoCountry
+}
+
+type alias struct {
+	Old, New string
+}
+
+func main() {
+	registryFile := flag.String("registry", "countryids.csv", "registry of permanent IDs, appended to for new countries")
+	out := flag.String("out", "enum.go", "file to generate")
+	flag.Parse()
+
+	registry, err := os.ReadFile(*registryFile)
+	if err != nil {
+		fail(err)
+	}
+	src, added, err := Generate(isoCSV, registry)
+	if err != nil {
+		fail(err)
+	}
+	if len(added) > 0 {
+		f, err := os.OpenFile(*registryFile, os.O_APPEND|os.O_WRONLY, 0)
+		if err != nil {
+			fail(err)
+		}
+		for _, entry := range added {
+			fmt.Fprintf(f, "%d,%s,active\n", entry.ID, entry.Alpha2)
+			fmt.Printf("gencountries: assigned ID %d to %s\n", entry.ID, entry.Alpha2)
+		}
+		if err := f.Close(); err != nil {
+			fail(err)
+		}
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "gencountries:", err)
+	os.Exit(1)
+}
+
+// Returns the source of enum.go for an ISO list and a registry, and the
+// registry entries that have to be appended for countries that had no ID.
+func Generate(iso, registry []byte) ([]byte, []countryids.Entry, error) {
+	countries, err := parseISO(iso)
+	if err != nil {
+		return nil, nil, fmt.Errorf("iso3166.csv: %w", err)
+	}
+	entries, err := countryids.Parse(registry)
+	if err != nil {
+		return nil, nil, fmt.Errorf("countryids.csv: %w", err)
+	}
+
+	byAlpha2 := map[string]isoCountry{}
+	for _, c := range countries {
+		byAlpha2[c.Alpha2] = c
+	}
+	data := enumData{}
+	assigned := map[string]bool{}
+	for _, entry := range entries {
+		if entry.Retired {
+			continue
+		}
+		c, ok := byAlpha2[entry.Alpha2]
+		if !ok {
+			return nil, nil, fmt.Errorf("%s (ID %d) is not in iso3166.csv, mark it retired in countryids.csv", entry.Alpha2, entry.ID)
+		}
+		data.Countries = append(data.Countries, generated{entry.ID, c})
+		assigned[entry.Alpha2] = true
+	}
+	added := []countryids.Entry{}
+	for _, c := range countries {
+		if assigned[c.Alpha2] {
+			continue
+		}
+		entry := countryids.Entry{ID: len(entries) + len(added), Alpha2: c.Alpha2}
+		added = append(added, entry)
+		data.Countries = append(data.Countries, generated{entry.ID, c})
+	}
+	data.Last = len(entries) + len(added)
+	for _, c := range data.Countries {
+		if c.ID >= bitsetIDs {
+			return nil, nil, fmt.Errorf("%s has ID %d, but bitsets, the default ValueFormat, only hold IDs below %d", c.Alpha2, c.ID, bitsetIDs)
+		}
+	}
+
+	for old, alpha2 := range aliases {
+		if _, ok := byAlpha2[alpha2]; ok {
+			data.Aliases = append(data.Aliases, alias{old, alpha2})
+		}
+	}
+	slices.SortFunc(data.Aliases, func(a, b alias) int { return strings.Compare(a.Old, b.Old) })
+
+	var b bytes.Buffer
+	if err := enumTemplate.Execute(&b, data); err != nil {
+		return nil, nil, err
+	}
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, nil, fmt.Errorf("generated code does not parse: %w", err)
+	}
+	return src, added, nil
+}
+
+// Reads the ISO list. Names lose any surrounding whitespace, codes must be
+// unique.
+func parseISO(data []byte) ([]isoCountry, error) {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 || !slices.Equal(records[0], []string{"name", "alpha2", "alpha3", "numeric"}) {
+		return nil, fmt.Errorf("expected the header name,alpha2,alpha3,numeric")
+	}
+
+	countries := []isoCountry{}
+	seen := map[string]bool{}
+	for _, record := range records[1:] {
+		numeric, err := strconv.Atoi(record[3])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", record[1], err)
+		}
+		c := isoCountry{
+			Name:    strings.TrimSpace(strings.TrimRight(record[0], " ")),
+			Alpha2:  record[1],
+			Alpha3:  record[2],
+			Numeric: numeric,
+		}
+		if len(c.Alpha2) != 2 || len(c.Alpha3) != 3 {
+			return nil, fmt.Errorf("%s: invalid codes", c.Name)
+		}
+		if seen[c.Alpha2] || seen[c.Alpha3] {
+			return nil, fmt.Errorf("%s: codes used twice", c.Name)
+		}
+		seen[c.Alpha2], seen[c.Alpha3] = true, true
+		countries = append(countries, c)
+	}
+	return countries, nil
+}
+
+var enumTemplate = template.Must(template.New("enum").Parse(`// Code generated by gencountries from iso3166.csv and countryids.csv. DO NOT EDIT.
+
+package utils
+
+// ISO 3166-1 countries, named after their alpha-2 code. The values are their
+// permanent IDs from countryids.csv.
+const (
+{{- range .Countries}}
+	COUNTRY_{{.Alpha2}} Country = {{.ID}} // {{.Name}}
+{{- end}}
+)
+
+// One more than the highest ID, retired ones included.
+const COUNTRY_PLACEHOLDER_LAST = {{.Last}}
+
+// Names of constants from before they were all named after the alpha-2 code.
+const (
+{{- range .Aliases}}
+	// Deprecated: use COUNTRY_{{.New}}.
+	COUNTRY_{{.Old}} = COUNTRY_{{.New}}
+{{- end}}
+)
+
+// The ISO 3166-1 data of each country, by ID. Retired IDs are left empty.
+var countryData = [COUNTRY_PLACEHOLDER_LAST]isoData{
+{{- range .Countries}}
+	COUNTRY_{{.Alpha2}}: { {{- printf "%q" .Name}}, "{{.Alpha2}}", "{{.Alpha3}}", {{.Numeric}}},
+{{- end}}
+}
+`))
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// enum.go must be what the generator makes of the checked-in files, so that
+// nobody edits it by hand or forgets to run go generate.
+func TestGeneratedUpToDate(t *testing.T) {
+	registry, err := os.ReadFile("../countryids.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, added, err := Generate(isoCSV, registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(added) > 0 {
+		t.Fatalf("countries without an ID: %v, run go generate ./utils", added)
+	}
+	enum, err := os.ReadFile("../enum.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(src, enum) {
+		t.Fatal("enum.go is out of date, run go generate ./utils")
+	}
+}
+
+const iso = `name,alpha2,alpha3,numeric
+Andorra ,AD,AND,020
+Zimbabwe,ZW,ZWE,716
+`
+
+func TestGenerateRegistry(t *testing.T) {
+	// ZW has no ID yet and gets the one after the retired ID 1.
+	src, added, err := Generate([]byte(iso), []byte("0,AD,active\n1,CS,retired\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(added) != 1 || added[0].ID != 2 || added[0].Alpha2 != "ZW" {
+		t.Fatalf("Expected ZW to get ID 2, obtained %v", added)
+	}
+	for _, want := range []string{
+		"COUNTRY_AD Country = 0 // Andorra\n",
+		"COUNTRY_ZW Country = 2 // Zimbabwe\n",
+		"COUNTRY_PLACEHOLDER_LAST = 3\n",
+		`COUNTRY_AD: {"Andorra", "AD", "AND", 20},`,
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("Expected the generated code to contain %q:\n%s", want, src)
+		}
+	}
+	if strings.Contains(string(src), "COUNTRY_CS") {
+		t.Error("Expected retired countries to be left out")
+	}
+
+	// A country with an ID that is no longer in the list must be retired.
+	if _, _, err := Generate([]byte(iso), []byte("0,AD,active\n1,CS,active\n")); err == nil {
+		t.Error("Expected CS to need retiring")
+	}
+	// The registry is checked like utils.ParseCountryIDs does.
+	if _, _, err := Generate([]byte(iso), []byte("0,AD,actve\n")); err == nil {
+		t.Error("Expected an unknown status to fail")
+	}
+}
+
+func TestGenerateBitsetLimit(t *testing.T) {
+	var registry strings.Builder
+	registry.WriteString("0,AD,active\n")
+	for id := 1; id < 256; id++ {
+		fmt.Fprintf(&registry, "%d,XX,retired\n", id)
+	}
+	if _, _, err := Generate([]byte(iso), []byte(registry.String())); err == nil {
+		t.Fatal("Expected ZW not to get ID 256")
+	}
+}
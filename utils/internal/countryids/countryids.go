@@ -0,0 +1,62 @@
+// Package countryids parses the registry of permanent country IDs,
+// utils/countryids.csv. It is shared by utils and the generator of enum.go,
+// which cannot import utils.
+package countryids
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+)
+
+// A row of the registry.
+type Entry struct {
+	ID      int
+	Alpha2  string
+	Retired bool
+}
+
+// Parses a registry. IDs must start at 0 and have no gaps, so that countries
+// can only be retired, not deleted. The status is active or retired, and no
+// two active entries share a code.
+func Parse(data []byte) ([]Entry, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comment = '#'
+	r.FieldsPerRecord = 3
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, len(records))
+	active := map[string]bool{}
+	for i, record := range records {
+		id, err := strconv.Atoi(record[0])
+		if err != nil {
+			return nil, err
+		}
+		if id != i {
+			return nil, fmt.Errorf("expected ID %d, obtained %d", i, id)
+		}
+		entry := Entry{ID: id, Alpha2: record[1]}
+		switch record[2] {
+		case "active":
+		case "retired":
+			entry.Retired = true
+		default:
+			return nil, fmt.Errorf("%d: unknown status %q", id, record[2])
+		}
+		if len(entry.Alpha2) != 2 {
+			return nil, fmt.Errorf("%d: invalid code %q", id, entry.Alpha2)
+		}
+		if !entry.Retired {
+			if active[entry.Alpha2] {
+				return nil, fmt.Errorf("%d: %s is already in use", id, entry.Alpha2)
+			}
+			active[entry.Alpha2] = true
+		}
+		entries[i] = entry
+	}
+	return entries, nil
+}
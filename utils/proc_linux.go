@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kernel clock ticks per second used by /proc/<pid>/stat. This is 100 on every
+// mainstream linux configuration and reading the real value needs cgo.
+const clockTicks = 100
+
+// Records the CPU time and disk I/O of another local process during each
+// iteration, from /proc/<pid>/stat and /proc/<pid>/io. Meant for the mysqld
+// process when the server runs on the same machine, see FindProcess.
+type ProcessSampler struct {
+	pid           int
+	cpu           time.Duration
+	readB, writeB float64
+}
+
+func NewProcessSampler(pid int) *ProcessSampler {
+	return &ProcessSampler{pid: pid}
+}
+
+func (s *ProcessSampler) Start() {
+	s.cpu, _ = processCPU(s.pid)
+	s.readB, s.writeB, _ = processIO(s.pid)
+}
+
+// Metrics that cannot be read, e.g. /proc/<pid>/io without the right
+// permissions, are left out.
+func (s *ProcessSampler) Stop() []Metric {
+	result := []Metric{}
+	if cpu, err := processCPU(s.pid); err == nil {
+		result = append(result, Metric{float64(cpu - s.cpu), "server-cpu-ns/op"})
+	}
+	if readB, writeB, err := processIO(s.pid); err == nil {
+		result = append(result,
+			Metric{readB - s.readB, "server-read-B/op"},
+			Metric{writeB - s.writeB, "server-write-B/op"},
+		)
+	}
+	return result
+}
+
+// Returns user plus system time of a process.
+func processCPU(pid int) (time.Duration, error) {
+	b, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, err
+	}
+	// The command name may contain spaces, so split after its closing
+	// parenthesis. utime and stime are fields 14 and 15, i.e. the 12th and
+	// 13th after the name.
+	fields := strings.Fields(string(b[bytes.LastIndexByte(b, ')')+1:]))
+	if len(fields) < 13 {
+		return 0, fmt.Errorf("unexpected /proc/%d/stat format", pid)
+	}
+	utime, err := strconv.ParseInt(fields[11], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	stime, err := strconv.ParseInt(fields[12], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(utime+stime) * time.Second / clockTicks, nil
+}
+
+// Returns the bytes a process caused to be read from and written to storage.
+func processIO(pid int) (readB, writeB float64, err error) {
+	f, err := os.Open(fmt.Sprintf("/proc/%d/io", pid))
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, _ := strings.Cut(scanner.Text(), ": ")
+		n, _ := strconv.ParseFloat(value, 64)
+		switch key {
+		case "read_bytes":
+			readB = n
+		case "write_bytes":
+			writeB = n
+		}
+	}
+	return readB, writeB, scanner.Err()
+}
+
+// Finds the pid of a running process by its command name, e.g. mysqld. This
+// also finds processes running in local docker containers.
+func FindProcess(name string) (int, error) {
+	comms, err := filepath.Glob("/proc/[0-9]*/comm")
+	if err != nil {
+		return 0, err
+	}
+	for _, comm := range comms {
+		b, err := os.ReadFile(comm)
+		if err != nil || strings.TrimSpace(string(b)) != name {
+			continue
+		}
+		return strconv.Atoi(filepath.Base(filepath.Dir(comm)))
+	}
+	return 0, fmt.Errorf("no %s process found", name)
+}
@@ -0,0 +1,22 @@
+//go:build !linux
+
+package utils
+
+import "fmt"
+
+// Only implemented on linux, records nothing elsewhere.
+type ProcessSampler struct{}
+
+func NewProcessSampler(pid int) *ProcessSampler {
+	return &ProcessSampler{}
+}
+
+func (s *ProcessSampler) Start() {}
+
+func (s *ProcessSampler) Stop() []Metric {
+	return nil
+}
+
+func FindProcess(name string) (int, error) {
+	return 0, fmt.Errorf("finding processes is only supported on linux")
+}
@@ -0,0 +1,162 @@
+package utils
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"slices"
+	"strings"
+)
+
+// A kind of profile ProfileIt can record.
+type ProfileKind string
+
+const (
+	ProfileCPU ProfileKind = "cpu"
+	// Live heap at the end of the run, after a GC.
+	ProfileHeap ProfileKind = "heap"
+	// All allocations made since the program started.
+	ProfileAllocs    ProfileKind = "allocs"
+	ProfileBlock     ProfileKind = "block"
+	ProfileMutex     ProfileKind = "mutex"
+	ProfileGoroutine ProfileKind = "goroutine"
+	// A runtime/trace execution trace, for `go tool trace`.
+	ProfileTrace ProfileKind = "trace"
+)
+
+var ProfileKinds = []ProfileKind{
+	ProfileCPU,
+	ProfileHeap,
+	ProfileAllocs,
+	ProfileBlock,
+	ProfileMutex,
+	ProfileGoroutine,
+	ProfileTrace,
+}
+
+// Which iterations of a timer to profile and how.
+type ProfileSchedule struct {
+	// Directory the profiles are written to. Files are named
+	// <timer name>-<iteration>.<kind>.out.
+	Dir string
+	// 1-based iteration numbers, e.g. 1, 11, 21.
+	Iterations []int
+	// Defaults to a CPU profile only.
+	Kinds []ProfileKind
+}
+
+// Makes TimeIt profile the scheduled iterations instead of timing them.
+func (t *Timer) SetProfileSchedule(schedule ProfileSchedule) *Timer {
+	t.profile = schedule
+	return t
+}
+
+// Returns the file name and kinds to profile the coming iteration of t with, if
+// it is scheduled.
+func (s *ProfileSchedule) next(t *Timer) (string, []ProfileKind, bool) {
+	iteration := len(t.durations) + 1
+	if !slices.Contains(s.Iterations, iteration) {
+		return "", nil, false
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		log.Fatal("could not create profile directory: ", err)
+	}
+
+	kinds := s.Kinds
+	if len(kinds) == 0 {
+		kinds = []ProfileKind{ProfileCPU}
+	}
+	name := strings.ReplaceAll(t.name, "/", "-")
+	filename := filepath.Join(s.Dir, fmt.Sprintf("%s-%d.out", name, iteration))
+	return filename, kinds, true
+}
+
+// Inserts the kind before the extension, e.g. read-1.out becomes
+// read-1.heap.out.
+func profileFilename(filename string, kind ProfileKind) string {
+	ext := filepath.Ext(filename)
+	return strings.TrimSuffix(filename, ext) + "." + string(kind) + ext
+}
+
+// Profiles a function. Since profiling will affect the performance of the
+// function, timing info will not be recorded.
+//
+// Without any kinds, a CPU profile is written to filename. Otherwise every kind
+// is written to its own file, named by inserting the kind before the extension
+// of filename.
+func (t *Timer) ProfileIt(fun func(), filename string, kinds ...ProfileKind) {
+	filenames := map[ProfileKind]string{}
+	if len(kinds) == 0 {
+		kinds = []ProfileKind{ProfileCPU}
+		filenames[ProfileCPU] = filename
+	} else {
+		for _, kind := range kinds {
+			filenames[kind] = profileFilename(filename, kind)
+		}
+	}
+
+	files := map[ProfileKind]*os.File{}
+	for _, kind := range kinds {
+		if !slices.Contains(ProfileKinds, kind) {
+			log.Fatalf("unknown profile kind %q", kind)
+		}
+		f, err := os.Create(filenames[kind])
+		if err != nil {
+			log.Fatalf("could not create %s profile: %v", kind, err)
+		}
+		defer f.Close()
+		files[kind] = f
+	}
+
+	for _, kind := range kinds {
+		switch kind {
+		case ProfileCPU:
+			if err := pprof.StartCPUProfile(files[kind]); err != nil {
+				log.Fatal("could not start CPU profile: ", err)
+			}
+		case ProfileTrace:
+			if err := trace.Start(files[kind]); err != nil {
+				log.Fatal("could not start trace: ", err)
+			}
+		case ProfileBlock:
+			runtime.SetBlockProfileRate(1)
+		case ProfileMutex:
+			runtime.SetMutexProfileFraction(1)
+		}
+	}
+
+	fun()
+
+	for _, kind := range kinds {
+		switch kind {
+		case ProfileCPU:
+			pprof.StopCPUProfile()
+		case ProfileTrace:
+			trace.Stop()
+		case ProfileHeap:
+			runtime.GC()
+			fallthrough
+		default:
+			if err := pprof.Lookup(string(kind)).WriteTo(files[kind], 0); err != nil {
+				log.Fatalf("could not write %s profile: %v", kind, err)
+			}
+		}
+		switch kind {
+		case ProfileBlock:
+			runtime.SetBlockProfileRate(0)
+		case ProfileMutex:
+			runtime.SetMutexProfileFraction(0)
+		}
+	}
+
+	// mark as invalid point so we can skip it when graphing, but don't use
+	// the wrong x coordinate.
+	t.durations = append(t.durations, -1)
+	for _, kind := range kinds {
+		fmt.Println("profile", filenames[kind], "done")
+	}
+}
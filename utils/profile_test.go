@@ -0,0 +1,44 @@
+package utils_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/podocarp/mysql-test-test/utils"
+)
+
+func TestProfileSchedule(t *testing.T) {
+	dir := t.TempDir()
+	timer := utils.NewTimer("read").SetSilent().SetProfileSchedule(utils.ProfileSchedule{
+		Dir:        dir,
+		Iterations: []int{1, 3},
+		Kinds:      utils.ProfileKinds,
+	})
+
+	work := func() {
+		s := []int{}
+		for i := range 100000 {
+			s = append(s, i)
+		}
+	}
+	for range 4 {
+		timer.TimeIt(work)
+	}
+
+	for _, iteration := range []string{"1", "3"} {
+		for _, kind := range utils.ProfileKinds {
+			filename := filepath.Join(dir, "read-"+iteration+"."+string(kind)+".out")
+			info, err := os.Stat(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Size() == 0 {
+				t.Fatalf("%s is empty", filename)
+			}
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "read-2.cpu.out")); err == nil {
+		t.Fatalf("Iteration 2 should not have been profiled")
+	}
+}
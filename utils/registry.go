@@ -0,0 +1,55 @@
+package utils
+
+import (
+	_ "embed"
+	"fmt"
+
+	"github.com/podocarp/mysql-test-test/utils/internal/countryids"
+)
+
+// The permanent ID of every country, see the comment at the top of the file.
+//
+//go:embed countryids.csv
+var countryIDsCSV []byte
+
+// An entry of countryids.csv.
+type CountryID struct {
+	ID      Country
+	Alpha2  string
+	Retired bool
+}
+
+var countryIDs = mustParseCountryIDs(countryIDsCSV)
+
+// Returns every entry of the registry in ID order, retired ones included.
+func CountryIDs() []CountryID {
+	return countryIDs
+}
+
+// Reports whether the country's ID was retired. Retired IDs are not reused,
+// so they can still be found in stored data.
+func (c Country) Retired() bool {
+	return c >= 0 && int(c) < len(countryIDs) && countryIDs[c].Retired
+}
+
+// Parses a registry in the format of countryids.csv. IDs must start at 0 and
+// have no gaps, so that countries can only be retired, not deleted.
+func ParseCountryIDs(data []byte) ([]CountryID, error) {
+	entries, err := countryids.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]CountryID, len(entries))
+	for i, entry := range entries {
+		ids[i] = CountryID{ID: Country(entry.ID), Alpha2: entry.Alpha2, Retired: entry.Retired}
+	}
+	return ids, nil
+}
+
+func mustParseCountryIDs(data []byte) []CountryID {
+	ids, err := ParseCountryIDs(data)
+	if err != nil {
+		panic(fmt.Errorf("countryids.csv: %w", err))
+	}
+	return ids
+}
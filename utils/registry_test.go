@@ -0,0 +1,307 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/podocarp/mysql-test-test/utils"
+)
+
+// Every ID ever assigned. IDs are stored in bitsets and JSON arrays, so an
+// entry here must never change; only append when adding a country to
+// countryids.csv.
+var assigned = []struct {
+	country utils.Country
+	alpha2  string
+}{
+	{utils.COUNTRY_AF, "AF"},
+	{utils.COUNTRY_AX, "AX"},
+	{utils.COUNTRY_AL, "AL"},
+	{utils.COUNTRY_DZ, "DZ"},
+	{utils.COUNTRY_AS, "AS"},
+	{utils.COUNTRY_AD, "AD"},
+	{utils.COUNTRY_AO, "AO"},
+	{utils.COUNTRY_AI, "AI"},
+	{utils.COUNTRY_AQ, "AQ"},
+	{utils.COUNTRY_AG, "AG"},
+	{utils.COUNTRY_AR, "AR"},
+	{utils.COUNTRY_AM, "AM"},
+	{utils.COUNTRY_AW, "AW"},
+	{utils.COUNTRY_AU, "AU"},
+	{utils.COUNTRY_AT, "AT"},
+	{utils.COUNTRY_AZ, "AZ"},
+	{utils.COUNTRY_BS, "BS"},
+	{utils.COUNTRY_BH, "BH"},
+	{utils.COUNTRY_BD, "BD"},
+	{utils.COUNTRY_BB, "BB"},
+	{utils.COUNTRY_BY, "BY"},
+	{utils.COUNTRY_BE, "BE"},
+	{utils.COUNTRY_BZ, "BZ"},
+	{utils.COUNTRY_BJ, "BJ"},
+	{utils.COUNTRY_BM, "BM"},
+	{utils.COUNTRY_BT, "BT"},
+	{utils.COUNTRY_BO, "BO"},
+	{utils.COUNTRY_BQ, "BQ"},
+	{utils.COUNTRY_BA, "BA"},
+	{utils.COUNTRY_BW, "BW"},
+	{utils.COUNTRY_BV, "BV"},
+	{utils.COUNTRY_BR, "BR"},
+	{utils.COUNTRY_IO, "IO"},
+	{utils.COUNTRY_BN, "BN"},
+	{utils.COUNTRY_BG, "BG"},
+	{utils.COUNTRY_BF, "BF"},
+	{utils.COUNTRY_BI, "BI"},
+	{utils.COUNTRY_CV, "CV"},
+	{utils.COUNTRY_KH, "KH"},
+	{utils.COUNTRY_CM, "CM"},
+	{utils.COUNTRY_CA, "CA"},
+	{utils.COUNTRY_KY, "KY"},
+	{utils.COUNTRY_CF, "CF"},
+	{utils.COUNTRY_TD, "TD"},
+	{utils.COUNTRY_CL, "CL"},
+	{utils.COUNTRY_CN, "CN"},
+	{utils.COUNTRY_CX, "CX"},
+	{utils.COUNTRY_CC, "CC"},
+	{utils.COUNTRY_CO, "CO"},
+	{utils.COUNTRY_KM, "KM"},
+	{utils.COUNTRY_CD, "CD"},
+	{utils.COUNTRY_CG, "CG"},
+	{utils.COUNTRY_CK, "CK"},
+	{utils.COUNTRY_CR, "CR"},
+	{utils.COUNTRY_CI, "CI"},
+	{utils.COUNTRY_HR, "HR"},
+	{utils.COUNTRY_CU, "CU"},
+	{utils.COUNTRY_CW, "CW"},
+	{utils.COUNTRY_CY, "CY"},
+	{utils.COUNTRY_CZ, "CZ"},
+	{utils.COUNTRY_DK, "DK"},
+	{utils.COUNTRY_DJ, "DJ"},
+	{utils.COUNTRY_DM, "DM"},
+	{utils.COUNTRY_DO, "DO"},
+	{utils.COUNTRY_EC, "EC"},
+	{utils.COUNTRY_EG, "EG"},
+	{utils.COUNTRY_SV, "SV"},
+	{utils.COUNTRY_GQ, "GQ"},
+	{utils.COUNTRY_ER, "ER"},
+	{utils.COUNTRY_EE, "EE"},
+	{utils.COUNTRY_SZ, "SZ"},
+	{utils.COUNTRY_ET, "ET"},
+	{utils.COUNTRY_FK, "FK"},
+	{utils.COUNTRY_FO, "FO"},
+	{utils.COUNTRY_FJ, "FJ"},
+	{utils.COUNTRY_FI, "FI"},
+	{utils.COUNTRY_FR, "FR"},
+	{utils.COUNTRY_GF, "GF"},
+	{utils.COUNTRY_PF, "PF"},
+	{utils.COUNTRY_TF, "TF"},
+	{utils.COUNTRY_GA, "GA"},
+	{utils.COUNTRY_GM, "GM"},
+	{utils.COUNTRY_GE, "GE"},
+	{utils.COUNTRY_DE, "DE"},
+	{utils.COUNTRY_GH, "GH"},
+	{utils.COUNTRY_GI, "GI"},
+	{utils.COUNTRY_GR, "GR"},
+	{utils.COUNTRY_GL, "GL"},
+	{utils.COUNTRY_GD, "GD"},
+	{utils.COUNTRY_GP, "GP"},
+	{utils.COUNTRY_GU, "GU"},
+	{utils.COUNTRY_GT, "GT"},
+	{utils.COUNTRY_GG, "GG"},
+	{utils.COUNTRY_GN, "GN"},
+	{utils.COUNTRY_GW, "GW"},
+	{utils.COUNTRY_GY, "GY"},
+	{utils.COUNTRY_HT, "HT"},
+	{utils.COUNTRY_HM, "HM"},
+	{utils.COUNTRY_HN, "HN"},
+	{utils.COUNTRY_HK, "HK"},
+	{utils.COUNTRY_HU, "HU"},
+	{utils.COUNTRY_IS, "IS"},
+	{utils.COUNTRY_IN, "IN"},
+	{utils.COUNTRY_ID, "ID"},
+	{utils.COUNTRY_IR, "IR"},
+	{utils.COUNTRY_IQ, "IQ"},
+	{utils.COUNTRY_IE, "IE"},
+	{utils.COUNTRY_IM, "IM"},
+	{utils.COUNTRY_IL, "IL"},
+	{utils.COUNTRY_IT, "IT"},
+	{utils.COUNTRY_JM, "JM"},
+	{utils.COUNTRY_JP, "JP"},
+	{utils.COUNTRY_JE, "JE"},
+	{utils.COUNTRY_JO, "JO"},
+	{utils.COUNTRY_KZ, "KZ"},
+	{utils.COUNTRY_KE, "KE"},
+	{utils.COUNTRY_KI, "KI"},
+	{utils.COUNTRY_KP, "KP"},
+	{utils.COUNTRY_KR, "KR"},
+	{utils.COUNTRY_KW, "KW"},
+	{utils.COUNTRY_KG, "KG"},
+	{utils.COUNTRY_LA, "LA"},
+	{utils.COUNTRY_LV, "LV"},
+	{utils.COUNTRY_LB, "LB"},
+	{utils.COUNTRY_LS, "LS"},
+	{utils.COUNTRY_LR, "LR"},
+	{utils.COUNTRY_LY, "LY"},
+	{utils.COUNTRY_LI, "LI"},
+	{utils.COUNTRY_LT, "LT"},
+	{utils.COUNTRY_LU, "LU"},
+	{utils.COUNTRY_MO, "MO"},
+	{utils.COUNTRY_MG, "MG"},
+	{utils.COUNTRY_MW, "MW"},
+	{utils.COUNTRY_MY, "MY"},
+	{utils.COUNTRY_MV, "MV"},
+	{utils.COUNTRY_ML, "ML"},
+	{utils.COUNTRY_MT, "MT"},
+	{utils.COUNTRY_MH, "MH"},
+	{utils.COUNTRY_MQ, "MQ"},
+	{utils.COUNTRY_MR, "MR"},
+	{utils.COUNTRY_MU, "MU"},
+	{utils.COUNTRY_YT, "YT"},
+	{utils.COUNTRY_MX, "MX"},
+	{utils.COUNTRY_FM, "FM"},
+	{utils.COUNTRY_MD, "MD"},
+	{utils.COUNTRY_MC, "MC"},
+	{utils.COUNTRY_MN, "MN"},
+	{utils.COUNTRY_ME, "ME"},
+	{utils.COUNTRY_MS, "MS"},
+	{utils.COUNTRY_MA, "MA"},
+	{utils.COUNTRY_MZ, "MZ"},
+	{utils.COUNTRY_MM, "MM"},
+	{utils.COUNTRY_NA, "NA"},
+	{utils.COUNTRY_NR, "NR"},
+	{utils.COUNTRY_NP, "NP"},
+	{utils.COUNTRY_NL, "NL"},
+	{utils.COUNTRY_NC, "NC"},
+	{utils.COUNTRY_NZ, "NZ"},
+	{utils.COUNTRY_NI, "NI"},
+	{utils.COUNTRY_NE, "NE"},
+	{utils.COUNTRY_NG, "NG"},
+	{utils.COUNTRY_NU, "NU"},
+	{utils.COUNTRY_NF, "NF"},
+	{utils.COUNTRY_MK, "MK"},
+	{utils.COUNTRY_MP, "MP"},
+	{utils.COUNTRY_NO, "NO"},
+	{utils.COUNTRY_OM, "OM"},
+	{utils.COUNTRY_PK, "PK"},
+	{utils.COUNTRY_PW, "PW"},
+	{utils.COUNTRY_PS, "PS"},
+	{utils.COUNTRY_PA, "PA"},
+	{utils.COUNTRY_PG, "PG"},
+	{utils.COUNTRY_PY, "PY"},
+	{utils.COUNTRY_PE, "PE"},
+	{utils.COUNTRY_PH, "PH"},
+	{utils.COUNTRY_PN, "PN"},
+	{utils.COUNTRY_PL, "PL"},
+	{utils.COUNTRY_PT, "PT"},
+	{utils.COUNTRY_PR, "PR"},
+	{utils.COUNTRY_QA, "QA"},
+	{utils.COUNTRY_RE, "RE"},
+	{utils.COUNTRY_RO, "RO"},
+	{utils.COUNTRY_RU, "RU"},
+	{utils.COUNTRY_RW, "RW"},
+	{utils.COUNTRY_BL, "BL"},
+	{utils.COUNTRY_SH, "SH"},
+	{utils.COUNTRY_KN, "KN"},
+	{utils.COUNTRY_LC, "LC"},
+	{utils.COUNTRY_MF, "MF"},
+	{utils.COUNTRY_PM, "PM"},
+	{utils.COUNTRY_VC, "VC"},
+	{utils.COUNTRY_WS, "WS"},
+	{utils.COUNTRY_SM, "SM"},
+	{utils.COUNTRY_ST, "ST"},
+	{utils.COUNTRY_SA, "SA"},
+	{utils.COUNTRY_SN, "SN"},
+	{utils.COUNTRY_RS, "RS"},
+	{utils.COUNTRY_SC, "SC"},
+	{utils.COUNTRY_SL, "SL"},
+	{utils.COUNTRY_SG, "SG"},
+	{utils.COUNTRY_SX, "SX"},
+	{utils.COUNTRY_SK, "SK"},
+	{utils.COUNTRY_SI, "SI"},
+	{utils.COUNTRY_SB, "SB"},
+	{utils.COUNTRY_SO, "SO"},
+	{utils.COUNTRY_ZA, "ZA"},
+	{utils.COUNTRY_GS, "GS"},
+	{utils.COUNTRY_SS, "SS"},
+	{utils.COUNTRY_ES, "ES"},
+	{utils.COUNTRY_LK, "LK"},
+	{utils.COUNTRY_SD, "SD"},
+	{utils.COUNTRY_SR, "SR"},
+	{utils.COUNTRY_SJ, "SJ"},
+	{utils.COUNTRY_SE, "SE"},
+	{utils.COUNTRY_CH, "CH"},
+	{utils.COUNTRY_SY, "SY"},
+	{utils.COUNTRY_TJ, "TJ"},
+	{utils.COUNTRY_TZ, "TZ"},
+	{utils.COUNTRY_TH, "TH"},
+	{utils.COUNTRY_TL, "TL"},
+	{utils.COUNTRY_TG, "TG"},
+	{utils.COUNTRY_TK, "TK"},
+	{utils.COUNTRY_TO, "TO"},
+	{utils.COUNTRY_TT, "TT"},
+	{utils.COUNTRY_TN, "TN"},
+	{utils.COUNTRY_TR, "TR"},
+	{utils.COUNTRY_TM, "TM"},
+	{utils.COUNTRY_TC, "TC"},
+	{utils.COUNTRY_TV, "TV"},
+	{utils.COUNTRY_UG, "UG"},
+	{utils.COUNTRY_UA, "UA"},
+	{utils.COUNTRY_AE, "AE"},
+	{utils.COUNTRY_GB, "GB"},
+	{utils.COUNTRY_UM, "UM"},
+	{utils.COUNTRY_US, "US"},
+	{utils.COUNTRY_UY, "UY"},
+	{utils.COUNTRY_UZ, "UZ"},
+	{utils.COUNTRY_VU, "VU"},
+	{utils.COUNTRY_VE, "VE"},
+	{utils.COUNTRY_VN, "VN"},
+	{utils.COUNTRY_VG, "VG"},
+	{utils.COUNTRY_VI, "VI"},
+	{utils.COUNTRY_WF, "WF"},
+	{utils.COUNTRY_YE, "YE"},
+	{utils.COUNTRY_ZM, "ZM"},
+	{utils.COUNTRY_ZW, "ZW"},
+	{utils.COUNTRY_EH, "EH"},
+	{utils.COUNTRY_TW, "TW"},
+	{utils.COUNTRY_VA, "VA"},
+}
+
+func TestCountryIDsStable(t *testing.T) {
+	ids := utils.CountryIDs()
+	if len(ids) < len(assigned) {
+		t.Fatalf("countryids.csv has %d entries, %d were assigned", len(ids), len(assigned))
+	}
+	for i, a := range assigned {
+		if a.country != utils.Country(i) {
+			t.Errorf("%s: constant changed from %d to %d", a.alpha2, i, a.country)
+		}
+		if ids[i].Alpha2 != a.alpha2 {
+			t.Errorf("ID %d: countryids.csv changed it from %s to %s", i, a.alpha2, ids[i].Alpha2)
+		}
+	}
+	if utils.COUNTRY_PLACEHOLDER_LAST != len(ids) {
+		t.Errorf("COUNTRY_PLACEHOLDER_LAST is %d, countryids.csv has %d entries", utils.COUNTRY_PLACEHOLDER_LAST, len(ids))
+	}
+}
+
+func TestParseCountryIDs(t *testing.T) {
+	valid := "# comment\n0,AF,active\n1,CS,retired\n2,CS,active\n"
+	ids, err := utils.ParseCountryIDs([]byte(valid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 3 || !ids[1].Retired || ids[2].Retired {
+		t.Fatalf("Unexpected registry %v", ids)
+	}
+
+	invalid := []string{
+		"1,AF,active\n",              // does not start at 0
+		"0,AF,active\n2,AX,active\n", // gap
+		"0,AF,active\n1,AF,active\n", // code used twice
+		"0,AF,removed\n",             // unknown status
+		"0,AFG,active\n",             // not alpha-2
+	}
+	for _, data := range invalid {
+		if _, err := utils.ParseCountryIDs([]byte(data)); err == nil {
+			t.Errorf("Expected %q to be invalid", data)
+		}
+	}
+}
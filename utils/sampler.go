@@ -0,0 +1,143 @@
+package utils
+
+import (
+	"math"
+	"runtime/metrics"
+	"slices"
+)
+
+// A single measurement taken for an iteration.
+type Metric struct {
+	Value float64
+	Unit  string
+}
+
+// A Sampler takes extra measurements around every iteration timed by a Timer.
+// The measurements are recorded as custom metrics, see Timer.ReportMetric.
+type Sampler interface {
+	// Called right before the timed function runs.
+	Start()
+	// Called right after it returns, with whatever was measured since Start.
+	Stop() []Metric
+}
+
+// Adds a sampler to the timer. Samplers are started in the order they were
+// added and stopped in reverse, so earlier samplers include the overhead of
+// later ones.
+func (t *Timer) AddSampler(s Sampler) *Timer {
+	t.samplers = append(t.samplers, s)
+	return t
+}
+
+// Adds a sampler that is started before and stopped after all samplers added
+// so far, the defaults of NewTimer included, so that the work it does itself,
+// e.g. querying the server, is left out of their measurements.
+func (t *Timer) AddOuterSampler(s Sampler) *Timer {
+	t.samplers = slices.Insert(t.samplers, 0, s)
+	return t
+}
+
+// Runtime metrics read by RuntimeSampler and the units they are reported as.
+// B/op and allocs/op use the same units as `go test -benchmem` so benchstat
+// picks them up.
+var runtimeMetrics = []struct {
+	name string
+	unit string
+	// Report the value at the end of the iteration instead of the difference.
+	absolute bool
+}{
+	{"/gc/heap/allocs:bytes", "B/op", false},
+	{"/gc/heap/allocs:objects", "allocs/op", false},
+	{"/gc/cycles/total:gc-cycles", "gc-cycles/op", false},
+	{"/sched/pauses/total/gc:seconds", "gc-pause-ns/op", false},
+	{"/memory/classes/heap/objects:bytes", "heap-bytes", true},
+}
+
+// Records how much the go runtime allocated, how many GC cycles ran and how
+// long they paused the program during each iteration, plus the heap in use at
+// the end of it.
+type RuntimeSampler struct {
+	samples []metrics.Sample
+	before  []float64
+}
+
+func NewRuntimeSampler() *RuntimeSampler {
+	supported := map[string]bool{}
+	for _, desc := range metrics.All() {
+		supported[desc.Name] = true
+	}
+
+	s := &RuntimeSampler{}
+	for _, m := range runtimeMetrics {
+		// Keep indices lined up with runtimeMetrics, unsupported metrics
+		// read as KindBad and are skipped.
+		name := m.name
+		if !supported[name] {
+			name = "/unsupported" + name
+		}
+		s.samples = append(s.samples, metrics.Sample{Name: name})
+	}
+	s.before = make([]float64, len(s.samples))
+	return s
+}
+
+func (s *RuntimeSampler) Start() {
+	metrics.Read(s.samples)
+	for i, sample := range s.samples {
+		s.before[i] = sampleValue(sample.Value)
+	}
+}
+
+func (s *RuntimeSampler) Stop() []Metric {
+	metrics.Read(s.samples)
+	result := []Metric{}
+	for i, sample := range s.samples {
+		if sample.Value.Kind() == metrics.KindBad {
+			continue
+		}
+		value := sampleValue(sample.Value)
+		if !runtimeMetrics[i].absolute {
+			value -= s.before[i]
+		}
+		if runtimeMetrics[i].unit == "gc-pause-ns/op" {
+			value *= 1e9
+		}
+		result = append(result, Metric{value, runtimeMetrics[i].unit})
+	}
+	return result
+}
+
+func sampleValue(v metrics.Value) float64 {
+	switch v.Kind() {
+	case metrics.KindUint64:
+		return float64(v.Uint64())
+	case metrics.KindFloat64:
+		return v.Float64()
+	case metrics.KindFloat64Histogram:
+		return histogramSum(v.Float64Histogram())
+	}
+	return 0
+}
+
+// Estimates the sum of all values in a histogram from the bucket midpoints. The
+// runtime only exposes pause times as histograms.
+func histogramSum(h *metrics.Float64Histogram) float64 {
+	sum := 0.0
+	for i, count := range h.Counts {
+		if count == 0 {
+			continue
+		}
+		lo, hi := h.Buckets[i], h.Buckets[i+1]
+		var mid float64
+		switch {
+		case math.IsInf(lo, -1):
+			mid = hi
+		case math.IsInf(hi, 1):
+			mid = lo
+		default:
+			mid = (lo + hi) / 2
+		}
+		sum += mid * float64(count)
+	}
+	return sum
+}
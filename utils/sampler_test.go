@@ -0,0 +1,58 @@
+package utils_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/podocarp/mysql-test-test/utils"
+)
+
+var sink [][]byte
+
+func TestRuntimeSampler(t *testing.T) {
+	timer := utils.NewTimer("alloc").SetSilent()
+	timer.TimeIt(func() {
+		for range 1000 {
+			sink = append(sink, make([]byte, 1024))
+		}
+	})
+	sink = nil
+
+	// The runtime only publishes allocations once per-P caches are flushed,
+	// so allow some slack.
+	if allocated := timer.Metric("B/op")[0]; allocated < 900*1024 {
+		t.Fatalf("Expected about 1MB allocated, obtained %v", allocated)
+	}
+	if allocs := timer.Metric("allocs/op")[0]; allocs < 900 {
+		t.Fatalf("Expected about 1000 allocations, obtained %v", allocs)
+	}
+	if heap := timer.Metric("heap-bytes")[0]; heap <= 0 {
+		t.Fatalf("Expected heap in use, obtained %v", heap)
+	}
+}
+
+type orderSampler struct {
+	name string
+	log  *[]string
+}
+
+func (s orderSampler) Start() { *s.log = append(*s.log, "start "+s.name) }
+
+func (s orderSampler) Stop() []utils.Metric {
+	*s.log = append(*s.log, "stop "+s.name)
+	return nil
+}
+
+func TestAddOuterSampler(t *testing.T) {
+	log := []string{}
+	timer := utils.NewTimer("order").SetSilent().
+		AddSampler(orderSampler{"inner", &log}).
+		AddOuterSampler(orderSampler{"outer", &log}).
+		AddOuterSampler(orderSampler{"outermost", &log})
+	timer.TimeIt(func() { log = append(log, "run") })
+
+	expected := []string{"start outermost", "start outer", "start inner", "run", "stop inner", "stop outer", "stop outermost"}
+	if !slices.Equal(log, expected) {
+		t.Fatalf("Expected %v, obtained %v", expected, log)
+	}
+}
@@ -2,9 +2,9 @@ package utils
 
 import (
 	"fmt"
-	"log"
+	"math"
 	"os"
-	"runtime/pprof"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -14,18 +14,49 @@ import (
 )
 
 type Timer struct {
-	durations []int64
+	durations []time.Duration
 	name      string
 	silent    bool
+	// Extra measurements reported for each iteration, keyed by unit. Every
+	// series lines up with durations and is padded with NaN for iterations
+	// that did not report that unit.
+	metrics map[string][]float64
+	// Units in the order they were first reported, so output is stable.
+	units []string
+	// Iterations that are profiled instead of timed.
+	profile ProfileSchedule
+	// Take extra measurements around every timed iteration.
+	samplers []Sampler
+	// How TimeItErr handles and classifies errors.
+	errorPolicy ErrorPolicy
+	retries     int
+	classify    func(error) string
+	errors      []IterationError
 }
 
+// Creates a timer that also records the go runtime's allocation and GC
+// metrics and the CPU time of this process for every iteration, see
+// RuntimeSampler and RusageSampler.
 func NewTimer(name string) *Timer {
 	return &Timer{
-		durations: []int64{},
+		durations: []time.Duration{},
 		name:      name,
+		metrics:   map[string][]float64{},
+		samplers:  []Sampler{NewRuntimeSampler(), NewRusageSampler()},
 	}
 }
 
+func (t *Timer) Name() string {
+	return t.name
+}
+
+// Renames the timer, e.g. to tell apart timers of the same name loaded from
+// different files.
+func (t *Timer) SetName(name string) *Timer {
+	t.name = name
+	return t
+}
+
 // Stops TimeIt from printing the time taken each call.
 func (t *Timer) SetSilent() *Timer {
 	t.silent = true
@@ -34,35 +65,141 @@ func (t *Timer) SetSilent() *Timer {
 
 // Runs the function and notes down its runtime
 func (t *Timer) TimeIt(fun func()) {
+	if filename, kinds, ok := t.profile.next(t); ok {
+		t.ProfileIt(fun, filename, kinds...)
+		return
+	}
+
+	for _, s := range t.samplers {
+		s.Start()
+	}
 	now := time.Now()
 	fun()
 	timeTaken := time.Since(now)
-	t.durations = append(t.durations, timeTaken.Milliseconds())
+	t.durations = append(t.durations, timeTaken)
+	for i := len(t.samplers) - 1; i >= 0; i-- {
+		for _, m := range t.samplers[i].Stop() {
+			t.ReportMetric(m.Value, m.Unit)
+		}
+	}
 	if !t.silent {
 		fmt.Printf("%s time taken: %v\n", t.name, timeTaken)
 	}
 }
 
-// Profiles a function. Since profiling will affect the performance of the
-// function, timing info will not be recorded.
-func (t *Timer) ProfileIt(fun func(), filename string) {
-	f, err := os.Create(filename)
-	if err != nil {
-		log.Fatal("could not create CPU profile: ", err)
+// Reports whether the most recent iteration has no timing info, because it was
+// profiled or failed.
+func (t *Timer) Untimed() bool {
+	return len(t.durations) > 0 && t.durations[len(t.durations)-1] < 0
+}
+
+// Returns the sum of all recorded durations, leaving out untimed iterations.
+func (t *Timer) Total() time.Duration {
+	var total time.Duration
+	for _, duration := range t.durations {
+		if duration >= 0 {
+			total += duration
+		}
 	}
-	defer f.Close()
+	return total
+}
 
-	if err := pprof.StartCPUProfile(f); err != nil {
-		log.Fatal("could not start CPU profile: ", err)
+// Returns the number of timed iterations. Untimed ones are left out, zero
+// durations are not.
+func (t *Timer) Timed() int {
+	n := 0
+	for _, duration := range t.durations {
+		if duration >= 0 {
+			n++
+		}
 	}
+	return n
+}
 
-	fun()
+// Returns the mean of all recorded durations, leaving out untimed iterations.
+func (t *Timer) Mean() time.Duration {
+	n := t.Timed()
+	if n == 0 {
+		return 0
+	}
+	return t.Total() / time.Duration(n)
+}
+
+// Forgets the most recent iteration, along with its metrics and errors. Used
+// when an iteration was cut short, e.g. by an interrupt, and its numbers would
+// be misleading.
+func (t *Timer) Discard() {
+	if len(t.durations) == 0 {
+		return
+	}
+	t.durations = t.durations[:len(t.durations)-1]
+	n := len(t.durations)
+
+	units := []string{}
+	for _, unit := range t.units {
+		series := t.metrics[unit]
+		if len(series) > n {
+			series = series[:n]
+		}
+		if !slices.ContainsFunc(series, func(v float64) bool { return !math.IsNaN(v) }) {
+			delete(t.metrics, unit)
+			continue
+		}
+		t.metrics[unit] = series
+		units = append(units, unit)
+	}
+	t.units = units
+
+	errors := []IterationError{}
+	for _, e := range t.errors {
+		if e.Iteration <= n {
+			errors = append(errors, e)
+		}
+	}
+	t.errors = errors
+}
 
-	pprof.StopCPUProfile()
-	// mark as invalid point so we can skip it when graphing, but don't use
-	// the wrong x coordinate.
-	t.durations = append(t.durations, -1)
-	fmt.Println("profile", filename, "done")
+// Records a custom metric for the most recent iteration, e.g. rows/op. This is
+// the Timer equivalent of testing.B.ReportMetric.
+func (t *Timer) ReportMetric(value float64, unit string) {
+	if len(t.durations) == 0 {
+		return
+	}
+	series, ok := t.metrics[unit]
+	if !ok {
+		t.units = append(t.units, unit)
+	}
+	for len(series) < len(t.durations) {
+		series = append(series, math.NaN())
+	}
+	series[len(t.durations)-1] = value
+	t.metrics[unit] = series
+}
+
+// Returns the units of all custom metrics, in the order they were reported.
+func (t *Timer) Units() []string {
+	return t.units
+}
+
+// Reports whether any iteration reported a value other than 0 for the unit.
+func (t *Timer) NonZero(unit string) bool {
+	for _, v := range t.metrics[unit] {
+		if v != 0 && !math.IsNaN(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the values of a custom metric, one per iteration. Iterations that did
+// not report the metric are NaN.
+func (t *Timer) Metric(unit string) []float64 {
+	series := make([]float64, len(t.durations))
+	copy(series, t.metrics[unit])
+	for i := len(t.metrics[unit]); i < len(series); i++ {
+		series[i] = math.NaN()
+	}
+	return series
 }
 
 // Saves the timing info recorded by all the runs of `TimeIt` in a file.
@@ -78,7 +215,7 @@ func (t *Timer) Save(filename string) {
 		if duration < 0 {
 			continue
 		}
-		_, err := f.WriteString(strconv.FormatInt(duration, 10))
+		_, err := f.WriteString(strconv.FormatInt(duration.Milliseconds(), 10))
 		if err != nil {
 			panic(err)
 		}
@@ -94,7 +231,7 @@ func (t *Timer) Echo() {
 		if duration < 0 {
 			continue
 		}
-		_, err := sb.WriteString(strconv.FormatInt(duration, 10))
+		_, err := sb.WriteString(strconv.FormatInt(duration.Milliseconds(), 10))
 		if err != nil {
 			panic(err)
 		}
@@ -104,6 +241,9 @@ func (t *Timer) Echo() {
 	}
 	fmt.Println(t.name, "timings:")
 	fmt.Println(sb.String())
+	if len(t.errors) > 0 {
+		fmt.Println(t.name, "errors:", t.ErrorCounts())
+	}
 }
 
 // Adds the duration data to the line graph and returns the number of items
@@ -118,7 +258,7 @@ func (t *Timer) AddToLineGraph(line *charts.Line) int {
 			}
 		} else {
 			data[i] = opts.LineData{
-				Value: duration,
+				Value: float64(duration) / float64(time.Millisecond),
 			}
 		}
 	}
@@ -128,7 +268,45 @@ func (t *Timer) AddToLineGraph(line *charts.Line) int {
 	return len(t.durations)
 }
 
-func GraphTimers(filename, title string, timers ...*Timer) {
+// Adds the values of a custom metric to the line graph and returns the number
+// of items added.
+func (t *Timer) AddMetricToLineGraph(line *charts.Line, unit string) int {
+	series := t.Metric(unit)
+	data := make([]opts.LineData, len(series))
+
+	for i, value := range series {
+		if math.IsNaN(value) {
+			data[i] = opts.LineData{
+				Value: nil,
+			}
+		} else {
+			data[i] = opts.LineData{
+				Value: value,
+			}
+		}
+	}
+
+	line.AddSeries(t.name, data)
+
+	return len(series)
+}
+
+// Plots the durations of all timers on one line graph.
+func LineGraph(title string, timers ...*Timer) *charts.Line {
+	return lineGraph(title, "Time taken (ms)", func(chart *charts.Line, t *Timer) int {
+		return t.AddToLineGraph(chart)
+	}, timers...)
+}
+
+// Plots a custom metric of all timers on one line graph, e.g. B/op to show
+// allocations next to the latency graph.
+func MetricGraph(title, unit string, timers ...*Timer) *charts.Line {
+	return lineGraph(title, unit, func(chart *charts.Line, t *Timer) int {
+		return t.AddMetricToLineGraph(chart, unit)
+	}, timers...)
+}
+
+func lineGraph(title, yName string, add func(*charts.Line, *Timer) int, timers ...*Timer) *charts.Line {
 	chart := charts.NewLine()
 	chart.SetGlobalOptions(charts.WithTitleOpts(opts.Title{
 		Title: title,
@@ -136,7 +314,7 @@ func GraphTimers(filename, title string, timers ...*Timer) {
 
 	maxlen := 0
 	for _, timer := range timers {
-		len := timer.AddToLineGraph(chart)
+		len := add(chart, timer)
 		if len > maxlen {
 			maxlen = len
 		}
@@ -158,10 +336,14 @@ func GraphTimers(filename, title string, timers ...*Timer) {
 			Name: "Iteration",
 		}),
 		charts.WithYAxisOpts(opts.YAxis{
-			Name: "Time taken (ms)",
+			Name: yName,
 		}),
 	)
+	return chart
+}
 
+func GraphTimers(filename, title string, timers ...*Timer) {
+	chart := LineGraph(title, timers...)
 	f, _ := os.Create(filename)
 	chart.Render(f)
 	fmt.Println("Written output to", filename)
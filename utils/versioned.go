@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"encoding/binary"
+	"fmt"
+	"slices"
+)
+
+// First byte of every versioned encoding. It is neither [ nor whitespace, so
+// versioned values cannot be mistaken for JSON. A 32 byte value starting with
+// it is only read as versioned if it decodes, otherwise it is a raw bitset.
+const versionMagic = 0xCB
+
+// Versions of the binary encoding, the second byte of a versioned value.
+// Released versions must keep decoding exactly as they do; change the layout
+// by adding a version. testdata/countries pins the bytes of each.
+const (
+	// A bitset of any length. Bytes 2 and 3 are the number n of bitset bytes
+	// that follow, big endian. Country c is bit c%8 of bitset byte c/8, so
+	// ids are not limited to 256 and trailing zero bytes are left out.
+	version1 = 1
+	// A list of ids, for sets with few members. Bytes 2 and 3 are the number
+	// n of ids that follow, each two bytes big endian, in increasing order.
+	version2 = 2
+)
+
+// Bytes of magic, version and length.
+const versionHeader = 4
+
+// Encodes the countries in the layout of a version. Repeated countries are
+// stored once.
+func encodeVersioned(version byte, c Countries) ([]byte, error) {
+	ids := slices.Clone(c)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+	if len(ids) > 0 && (ids[0] < 0 || ids[len(ids)-1] > 0xFFFF) {
+		return nil, fmt.Errorf("countries must be between 0 and 65535")
+	}
+
+	var n int
+	var payload []byte
+	switch version {
+	case version1:
+		if len(ids) > 0 {
+			n = int(ids[len(ids)-1])/8 + 1
+		}
+		payload = make([]byte, n)
+		for _, id := range ids {
+			payload[id/8] |= 1 << (id % 8)
+		}
+	case version2:
+		n = len(ids)
+		payload = make([]byte, 2*n)
+		for i, id := range ids {
+			binary.BigEndian.PutUint16(payload[2*i:], uint16(id))
+		}
+	default:
+		return nil, fmt.Errorf("unknown encoding version %d", version)
+	}
+	// Only version 2 can get here, with every possible id.
+	if n > 0xFFFF {
+		return nil, fmt.Errorf("version %d holds at most 65535 countries, not %d", version, n)
+	}
+
+	b := make([]byte, versionHeader, versionHeader+len(payload)+1)
+	b[0], b[1] = versionMagic, version
+	binary.BigEndian.PutUint16(b[2:4], uint16(n))
+	b = append(b, payload...)
+	if len(b) == 32 {
+		// Keeps the value from being 32 bytes long, for columns that hold
+		// raw bitsets too. Decoders ignore trailing zeros, so a BINARY(32)
+		// column cutting it off changes nothing.
+		b = append(b, 0)
+	}
+	return b, nil
+}
+
+// The format of the given version.
+func versionedFormat(version byte) (Format, error) {
+	switch version {
+	case version1:
+		return FormatBinaryV1, nil
+	case version2:
+		return FormatBinaryV2, nil
+	}
+	return 0, fmt.Errorf("unknown encoding version %d", version)
+}
+
+// Decodes a value starting with versionMagic.
+func decodeVersioned(b []byte) (Countries, error) {
+	if len(b) < versionHeader || b[0] != versionMagic {
+		return nil, fmt.Errorf("not a versioned encoding")
+	}
+	version := b[1]
+	n := int(binary.BigEndian.Uint16(b[2:4]))
+	payload := b[versionHeader:]
+
+	countries := Countries{}
+	switch version {
+	case version1:
+		if len(payload) < n {
+			return nil, fmt.Errorf("version %d: expected %d bytes, obtained %d", version, n, len(payload))
+		}
+		for i, byt := range payload[:n] {
+			for bit := range 8 {
+				if byt&(1<<bit) != 0 {
+					countries = append(countries, Country(8*i+bit))
+				}
+			}
+		}
+		payload = payload[n:]
+	case version2:
+		if len(payload) < 2*n {
+			return nil, fmt.Errorf("version %d: expected %d bytes, obtained %d", version, 2*n, len(payload))
+		}
+		for i := range n {
+			countries = append(countries, Country(binary.BigEndian.Uint16(payload[2*i:])))
+		}
+		payload = payload[2*n:]
+	default:
+		return nil, fmt.Errorf("unknown encoding version %d", version)
+	}
+	for _, byt := range payload {
+		if byt != 0 {
+			return nil, fmt.Errorf("version %d: unexpected trailing bytes", version)
+		}
+	}
+	return countries, nil
+}
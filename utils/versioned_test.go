@@ -0,0 +1,164 @@
+package utils_test
+
+import (
+	"encoding/hex"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/podocarp/mysql-test-test/utils"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// Sets of countries whose encodings are pinned in testdata/countries.
+var goldenSets = map[string]utils.Countries{
+	"empty":    {},
+	"some":     {utils.COUNTRY_AD, utils.COUNTRY_FR, utils.COUNTRY_NU},
+	"repeated": {utils.COUNTRY_FR, utils.COUNTRY_FR, utils.COUNTRY_AD},
+	"first":    {0},
+	"last":     {255},
+	// 28 bitset bytes, which v1 pads so the value is not 32 bytes long.
+	"padded": {220},
+	// 14 ids, which v2 pads for the same reason.
+	"fourteen": {0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13},
+	// A JSON array of exactly 32 bytes, which is still not a bitset.
+	"json32": {10, 11, 12, 13, 14, 15, 16, 17, 18, 100},
+	// Beyond what fits in a 32 byte bitset.
+	"large": {3, 256, 1000, 65535},
+}
+
+var goldenFormats = []utils.Format{
+	utils.FormatBitset,
+	utils.FormatJSON,
+	utils.FormatBinaryV1,
+	utils.FormatBinaryV2,
+}
+
+// Every released format must keep encoding to, and decoding from, the same
+// bytes. Run with -update after adding a format or set, never to make a
+// changed layout pass.
+func TestGoldenEncodings(t *testing.T) {
+	for _, format := range goldenFormats {
+		for name, countries := range goldenSets {
+			filename := filepath.Join("testdata", "countries", format.String()+"-"+name+".hex")
+			v, err := countries.Encode(format)
+			if format == utils.FormatBitset && name == "large" {
+				if err == nil {
+					t.Errorf("%s: expected large ids not to fit", filename)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("%s: %v", filename, err)
+			}
+			var b []byte
+			switch v := v.(type) {
+			case []byte:
+				b = v
+			case string:
+				b = []byte(v)
+			}
+
+			if *update {
+				if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filename, []byte(hex.EncodeToString(b)+"\n"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			golden, err := os.ReadFile(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := hex.DecodeString(strings.TrimSpace(string(golden)))
+			if err != nil {
+				t.Fatalf("%s: %v", filename, err)
+			}
+			if string(b) != string(want) {
+				t.Errorf("%s: expected %x, obtained %x", filename, want, b)
+			}
+
+			versioned := format != utils.FormatBitset && format != utils.FormatJSON
+			if versioned && len(want) == 32 {
+				t.Errorf("%s: versioned encodings must not be 32 bytes long", filename)
+			}
+			expected := goldenSets[name]
+			if name == "repeated" && format != utils.FormatJSON {
+				expected = utils.Countries{utils.COUNTRY_AD, utils.COUNTRY_FR}
+			}
+			stored := [][]byte{want}
+			if len(want) < 32 && format != utils.FormatBitset || len(want) == 33 && versioned {
+				// As a BINARY(32) column pads it, or cuts off the padding of
+				// a versioned value whose natural length is 32.
+				binary32 := make([]byte, 32)
+				copy(binary32, want)
+				stored = append(stored, binary32)
+			}
+			for _, b := range stored {
+				if detected, err := utils.DetectFormat(b); err != nil || detected != format {
+					t.Errorf("%s: %x detected as %v, %v", filename, b, detected, err)
+				}
+				var decoded utils.Countries
+				if err := decoded.Scan(b); err != nil {
+					t.Fatalf("%s: %x: %v", filename, b, err)
+				}
+				assertCountriesEq(t, expected, decoded)
+			}
+		}
+	}
+}
+
+func TestVersionedInvalid(t *testing.T) {
+	invalid := []string{
+		"cb09000100",   // unknown version
+		"cb010002ff",   // bitset shorter than its length
+		"cb02000100",   // id list shorter than its length
+		"cb0100010101", // trailing bytes that are not padding
+		"cb01",         // no length
+	}
+	for _, s := range invalid {
+		b, _ := hex.DecodeString(s)
+		var countries utils.Countries
+		if err := countries.Scan(b); err == nil {
+			t.Errorf("Expected %s not to decode, obtained %v", s, countries)
+		}
+	}
+
+	countries := utils.Countries{-1}
+	if _, err := countries.Encode(utils.FormatBinaryV1); err == nil {
+		t.Error("Expected negative ids not to encode")
+	}
+}
+
+// The length field is two bytes, so version 2 fits all ids but one.
+func TestVersionedLimit(t *testing.T) {
+	countries := utils.Countries{}
+	for id := range 0xFFFF {
+		countries = append(countries, utils.Country(id))
+	}
+	for _, format := range []utils.Format{utils.FormatBinaryV1, utils.FormatBinaryV2} {
+		v, err := countries.Encode(format)
+		if err != nil {
+			t.Fatalf("%v: %v", format, err)
+		}
+		var decoded utils.Countries
+		if err := decoded.Scan(v); err != nil {
+			t.Fatalf("%v: %v", format, err)
+		}
+		if len(decoded) != len(countries) || decoded[len(decoded)-1] != 0xFFFE {
+			t.Fatalf("%v: expected %d countries up to 65534, obtained %d", format, len(countries), len(decoded))
+		}
+	}
+
+	countries = append(countries, 0xFFFF)
+	if _, err := countries.Encode(utils.FormatBinaryV2); err == nil {
+		t.Error("Expected all 65536 ids not to fit in version 2")
+	}
+	if _, err := countries.Encode(utils.FormatBinaryV1); err != nil {
+		t.Errorf("Expected all 65536 ids to fit in version 1, obtained %v", err)
+	}
+}